@@ -0,0 +1,149 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+)
+
+// RegisteredClaims contains the registered claim names defined in RFC 7519
+// It can be used as content directly or embedded in a struct containing additional claims
+type RegisteredClaims struct {
+	Issuer    string       `json:"iss,omitempty"`
+	Subject   string       `json:"sub,omitempty"`
+	Audience  *Audience    `json:"aud,omitempty"`
+	ExpiresAt *NumericDate `json:"exp,omitempty"`
+	NotBefore *NumericDate `json:"nbf,omitempty"`
+	IssuedAt  *NumericDate `json:"iat,omitempty"`
+	ID        string       `json:"jti,omitempty"`
+}
+
+// NumericDate is a point in time encoded as the number of seconds since the epoch as defined in RFC 7519
+type NumericDate struct {
+	time.Time
+}
+
+// NewNumericDate returns a NumericDate for t truncated to seconds
+func NewNumericDate(t time.Time) *NumericDate {
+	return &NumericDate{t.Truncate(time.Second)}
+}
+
+// MarshalJSON encodes the date as the number of seconds since the epoch
+func (d NumericDate) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(d.Unix(), 10)), nil
+}
+
+// UnmarshalJSON decodes a date from a number of seconds since the epoch
+// For compatibility with tokens containing a time.Time, RFC 3339 strings are accepted as well
+func (d *NumericDate) UnmarshalJSON(in []byte) error {
+	if len(in) > 0 && in[0] == '"' {
+		var t time.Time
+		err := json.Unmarshal(in, &t)
+		if err != nil {
+			return err
+		}
+		d.Time = t
+		return nil
+	}
+	f, err := strconv.ParseFloat(string(in), 64)
+	if err != nil {
+		return errors.New("numeric date has to be a number")
+	}
+	// Converting values outside the range of int64 is implementation-defined and could turn a date far in the future into one in the past
+	f = math.Round(f)
+	if !(f >= math.MinInt64 && f < math.MaxInt64) {
+		return errors.New("numeric date is out of range")
+	}
+	d.Time = time.Unix(int64(f), 0)
+	return nil
+}
+
+// Audience contains the values of the aud claim which may either be a single string or an array of strings
+// A decoded audience keeps the form it was decoded from, so it is encoded back to exactly that form
+type Audience struct {
+	Values []string
+	array  bool // Whether a single value was decoded from an array
+}
+
+// NewAudience returns an Audience containing values that is encoded as a string if it contains a single value and as an array otherwise
+func NewAudience(values ...string) *Audience {
+	return &Audience{Values: values}
+}
+
+// NewAudienceArray returns an Audience containing values that is always encoded as an array
+func NewAudienceArray(values ...string) *Audience {
+	return &Audience{Values: values, array: true}
+}
+
+// MarshalJSON encodes the audience in the form it was created or decoded in
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a.Values) == 1 && !a.array {
+		return json.Marshal(a.Values[0])
+	}
+	if a.Values == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(a.Values)
+}
+
+// UnmarshalJSON decodes an audience from either a string or an array of strings
+func (a *Audience) UnmarshalJSON(in []byte) error {
+	if len(in) > 0 && in[0] == '"' {
+		var s string
+		err := json.Unmarshal(in, &s)
+		if err != nil {
+			return err
+		}
+		*a = Audience{Values: []string{s}}
+		return nil
+	}
+	var s []string
+	err := json.Unmarshal(in, &s)
+	if err != nil {
+		return err
+	}
+	*a = Audience{Values: s, array: true}
+	return nil
+}
+
+// Contains reports whether the audience contains at least one of values
+// A nil audience does not contain any values
+func (a *Audience) Contains(values ...string) bool {
+	if a == nil {
+		return false
+	}
+	for _, v := range a.Values {
+		if contains(values, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// contentJSON returns the content of the token encoded as JSON
+// For decoded tokens the content is returned exactly as it appeared in the token
+func (jwt *JWT) contentJSON() ([]byte, error) {
+	if jwt.raw == nil {
+		return json.Marshal(jwt.Content)
+	}
+	return base64.RawURLEncoding.DecodeString(string(jwt.raw[bytes.IndexByte(jwt.raw, '.')+1:]))
+}
+
+// parseRegisteredClaims decodes the registered claims from content
+// Content that is not a JSON object cannot contain claims and is therefore ignored
+func parseRegisteredClaims(content []byte) (c RegisteredClaims, err error) {
+	if !isJSONObject(content) {
+		return
+	}
+	err = json.Unmarshal(content, &c)
+	return
+}
+
+func isJSONObject(data []byte) bool {
+	data = bytes.TrimLeft(data, " \t\r\n")
+	return len(data) > 0 && data[0] == '{'
+}
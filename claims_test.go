@@ -0,0 +1,202 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestNumericDate_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		d    *NumericDate
+		want []byte
+	}{
+		{"Epoch", NewNumericDate(time.Unix(0, 0)), []byte("0")},
+		{"Truncated", NewNumericDate(time.Unix(1516239022, 999999999)), []byte("1516239022")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.d)
+			if err != nil {
+				t.Errorf("NumericDate.MarshalJSON() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NumericDate.MarshalJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNumericDate_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []byte
+		want    time.Time
+		wantErr bool
+	}{
+		{"Integer", []byte("1516239022"), time.Unix(1516239022, 0), false},
+		{"Float", []byte("1516239022.6"), time.Unix(1516239023, 0), false},
+		{"RFC3339", []byte(`"2018-01-18T01:30:22Z"`), time.Unix(1516239022, 0), false},
+		{"InvalidString", []byte(`"yesterday"`), time.Time{}, true},
+		{"Bool", []byte("true"), time.Time{}, true},
+		{"OutOfRange", []byte("1e30"), time.Time{}, true},
+		{"NegativeOutOfRange", []byte("-1e30"), time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d NumericDate
+			err := json.Unmarshal(tt.in, &d)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NumericDate.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !d.Equal(tt.want) {
+				t.Errorf("NumericDate.UnmarshalJSON() = %v, want %v", d.Time, tt.want)
+			}
+		})
+	}
+}
+
+func TestAudience(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []byte
+		want    *Audience
+		out     []byte
+		wantErr bool
+	}{
+		{"String", []byte(`"service"`), NewAudience("service"), []byte(`"service"`), false},
+		{"Array", []byte(`["service1","service2"]`), NewAudienceArray("service1", "service2"), []byte(`["service1","service2"]`), false},
+		{"ArraySingleValue", []byte(`["service"]`), NewAudienceArray("service"), []byte(`["service"]`), false},
+		{"EmptyArray", []byte(`[]`), &Audience{Values: []string{}, array: true}, []byte(`[]`), false},
+		{"Number", []byte(`42`), nil, nil, true},
+		{"ArrayOfNumbers", []byte(`[42]`), nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a Audience
+			err := json.Unmarshal(tt.in, &a)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Audience.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(&a, tt.want) {
+				t.Errorf("Audience.UnmarshalJSON() = %v, want %v", a, tt.want)
+			}
+			out, err := json.Marshal(a)
+			if err != nil {
+				t.Errorf("Audience.MarshalJSON() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(out, tt.out) {
+				t.Errorf("Audience.MarshalJSON() = %s, want %s", out, tt.out)
+			}
+		})
+	}
+	if !NewAudience("a", "b").Contains("c", "b") || NewAudience("a").Contains("b") || (*Audience)(nil).Contains("a") {
+		t.Error("Audience.Contains() returned wrong result")
+	}
+
+	// The audience is omitted from registered claims unless it is set
+	claimTests := []struct {
+		name string
+		aud  *Audience
+		want string
+	}{
+		{"Unset", nil, `{"sub":"test"}`},
+		{"Empty", &Audience{}, `{"sub":"test","aud":[]}`},
+		{"String", NewAudience("service"), `{"sub":"test","aud":"service"}`},
+		{"Array", NewAudienceArray("service"), `{"sub":"test","aud":["service"]}`},
+	}
+	for _, tt := range claimTests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := json.Marshal(RegisteredClaims{Subject: "test", Audience: tt.aud})
+			if err != nil {
+				t.Fatalf("Failed to marshal claims: %s", err.Error())
+			}
+			if string(out) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationRegisteredClaims(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	type customClaims struct {
+		RegisteredClaims
+		Name string `json:"name"`
+	}
+	type timeClaims struct {
+		Expiry time.Time `json:"exp"`
+	}
+	now := time.Now()
+	tests := []struct {
+		name    string
+		content interface{}
+		wantErr bool
+	}{
+		{"Embedded", customClaims{RegisteredClaims{Subject: "test", ExpiresAt: NewNumericDate(now.Add(time.Minute))}, "test"}, false},
+		{"EmbeddedExpired", customClaims{RegisteredClaims{ExpiresAt: NewNumericDate(now.Add(-time.Minute))}, "test"}, true},
+		{"EmbeddedNotValidYet", customClaims{RegisteredClaims{NotBefore: NewNumericDate(now.Add(time.Minute))}, "test"}, true},
+		{"Struct", RegisteredClaims{ExpiresAt: NewNumericDate(now.Add(-time.Minute))}, true},
+		{"TimeExpired", timeClaims{now.Add(-time.Minute)}, true},
+		{"TimeValid", timeClaims{now.Add(time.Minute)}, false},
+		{"MapInvalidClaim", map[string]interface{}{"exp": "tomorrow"}, true},
+		{"NotBeforeOutOfRange", map[string]interface{}{"nbf": 1e30}, true},
+		{"ExpiryOutOfRange", map[string]interface{}{"exp": 1e30}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tok, err := New(tt.content)
+			if err != nil {
+				t.Fatalf("Failed to create token: %s", err.Error())
+			}
+			if err := tok.Validate(public); !errors.Is(err, ErrSignatureInvalid) {
+				t.Errorf("JWT.Validate() error = %v on token that has not been signed, want %v", err, ErrSignatureInvalid)
+			}
+			enc, err := s.Encode(&tok)
+			if err != nil {
+				t.Fatalf("Failed to encode token: %s", err.Error())
+			}
+			dec, err := Decode(string(enc))
+			if err != nil {
+				t.Fatalf("Failed to decode token: %s", err.Error())
+			}
+			if err := dec.Validate(public); (err != nil) != tt.wantErr {
+				t.Errorf("JWT.Validate() on decoded token error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Dates outside the range of int64 seconds are malformed instead of wrapping around
+	for _, claim := range []string{"nbf", "exp"} {
+		enc, err := s.Sign(map[string]interface{}{claim: 1e30})
+		if err != nil {
+			t.Fatalf("Failed to sign token: %s", err.Error())
+		}
+		dec, err := Decode(string(enc))
+		if err != nil {
+			t.Fatalf("Failed to decode token: %s", err.Error())
+		}
+		if err := dec.Validate(public); !errors.Is(err, ErrMalformed) {
+			t.Errorf("JWT.Validate() error = %v for %s out of range, want %v", err, claim, ErrMalformed)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package jwt
+
+import "time"
+
+// Clock provides the current time and allows replacing the system clock in tests
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc allows using a function such as time.Now as Clock
+type ClockFunc func() time.Time
+
+// Now returns the result of calling f
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,117 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestJWT_ValidateWithClock(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	issued := time.Date(2018, time.January, 1, 12, 0, 0, 0, time.UTC)
+	enc, err := s.Sign(RegisteredClaims{
+		IssuedAt:  NewNumericDate(issued),
+		NotBefore: NewNumericDate(issued.Add(time.Minute)),
+		ExpiresAt: NewNumericDate(issued.Add(time.Hour)),
+	})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+	dec, err := Decode(string(enc))
+	if err != nil {
+		t.Fatalf("Failed to decode token: %s", err.Error())
+	}
+	ks := NewMemoryKeySet()
+	err = ks.SetDefault(public)
+	if err != nil {
+		t.Fatalf("Failed to set default key: %s", err.Error())
+	}
+	tok, err := DecodeInto[RegisteredClaims](string(enc))
+	if err != nil {
+		t.Fatalf("Failed to decode token: %s", err.Error())
+	}
+	tests := []struct {
+		name    string
+		now     time.Time
+		leeway  time.Duration
+		wantErr error
+	}{
+		{"Valid", issued.Add(30 * time.Minute), 0, nil},
+		{"IssuedInFuture", issued.Add(-time.Second), 0, ErrNotYetValid},
+		{"IssuedInFutureWithinLeeway", issued.Add(-30 * time.Second), 2 * time.Minute, nil},
+		{"NotValidYet", issued.Add(30 * time.Second), 0, ErrNotYetValid},
+		{"NotValidYetWithinLeeway", issued.Add(30 * time.Second), time.Minute, nil},
+		{"NotBefore", issued.Add(time.Minute), 0, nil},
+		{"ExpiresAt", issued.Add(time.Hour), 0, ErrExpired},
+		{"Expired", issued.Add(time.Hour + time.Second), 0, ErrExpired},
+		{"ExpiredWithinLeeway", issued.Add(time.Hour + time.Second), time.Minute, nil},
+		{"ExpiredBeyondLeeway", issued.Add(time.Hour + time.Minute), time.Minute, ErrExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dec.ValidateWithClock(public, &testClock{tt.now}, tt.leeway)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("JWT.ValidateWithClock() error = %v, want %v", err, tt.wantErr)
+			}
+			err = dec.VerifyWithKeySetAndClock(ks, &testClock{tt.now}, tt.leeway)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("JWT.VerifyWithKeySetAndClock() error = %v, want %v", err, tt.wantErr)
+			}
+			err = tok.VerifyWithKeySetAndClock(ks, &testClock{tt.now}, tt.leeway)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Token.VerifyWithKeySetAndClock() error = %v, want %v", err, tt.wantErr)
+			}
+
+			v, err := NewVerifier(public, WithClock(ClockFunc(func() time.Time { return tt.now })), WithLeeway(tt.leeway))
+			if err != nil {
+				t.Fatalf("Failed to create verifier: %s", err.Error())
+			}
+			_, err = v.Verify(string(enc))
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Verifier.Verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := NewVerifier(public, WithClock(nil)); err == nil {
+		t.Error("NewVerifier() accepted nil clock")
+	}
+}
+
+func TestVerifier_MaxAgeWithClock(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	issued := time.Date(2018, time.January, 1, 12, 0, 0, 0, time.UTC)
+	enc, err := s.Sign(RegisteredClaims{IssuedAt: NewNumericDate(issued)})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+	clock := &testClock{issued.Add(time.Hour)}
+	v, err := NewVerifier(public, WithClock(clock), WithMaxAge(time.Hour), WithLeeway(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %s", err.Error())
+	}
+	if _, err := v.Verify(string(enc)); err != nil {
+		t.Errorf("Failed to verify token within maximum age: %s", err.Error())
+	}
+	clock.now = issued.Add(time.Hour + 2*time.Minute)
+	var expErr *ExpiredError
+	if _, err := v.Verify(string(enc)); !errors.As(err, &expErr) || !expErr.ExpiresAt.Equal(issued.Add(time.Hour)) {
+		t.Errorf("Verifier.Verify() error = %v, want token to expire after maximum age", err)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"strings"
+
+	jwt "github.com/fossoreslp/go-jwt-ed25519"
+)
+
+// decode writes the header and payload of a token to stdout without verifying it
+func decode(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	arg, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	token, err := readToken(arg, stdin)
+	if err != nil {
+		return err
+	}
+
+	// The sections are decoded directly, so tokens this package would reject can still be inspected
+	sections := strings.Split(token, ".")
+	if len(sections) != 3 {
+		return &jwt.MalformedError{Err: errors.New("token does not consist of three sections")}
+	}
+	header, err := decodeSection(sections[0])
+	if err != nil {
+		return err
+	}
+	payload, err := decodeSection(sections[1])
+	if err != nil {
+		return err
+	}
+	out, err := marshalJSON(struct {
+		Header  json.RawMessage `json:"header"`
+		Payload json.RawMessage `json:"payload"`
+	}{header, payload})
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(out)
+	return err
+}
+
+// decodeSection decodes a base64url encoded section and returns it as JSON
+// Sections that are not valid JSON are returned as JSON string
+func decodeSection(section string) (json.RawMessage, error) {
+	data, err := base64.RawURLEncoding.DecodeString(section)
+	if err != nil {
+		return nil, &jwt.MalformedError{Err: err}
+	}
+	if json.Valid(data) {
+		return data, nil
+	}
+	return json.Marshal(string(data))
+}
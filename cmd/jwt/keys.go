@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+
+	jwt "github.com/fossoreslp/go-jwt-ed25519"
+	"golang.org/x/crypto/ed25519"
+)
+
+// keygen generates an Ed25519 key pair and writes the private key to -out or stdout and the public key to -pub
+func keygen(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	format := fs.String("format", "pem", "output `format` of the keys, either pem or jwk")
+	kid := fs.String("kid", "", "key `ID` inserted into JWK output")
+	out := fs.String("out", "", "`file` to write the private key to instead of stdout")
+	pub := fs.String("pub", "", "`file` to write the public key to")
+	_, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return usageError{"keygen does not accept arguments"}
+	}
+	if *format != "pem" && *format != "jwk" {
+		return usageError{"unsupported format " + *format}
+	}
+	if *kid != "" && *format != "jwk" {
+		return usageError{"key IDs are only supported for JWK output"}
+	}
+
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return err
+	}
+	var privateData, publicData []byte
+	if *format == "pem" {
+		privateData, err = jwt.MarshalPrivateKeyPEM(key)
+		if err != nil {
+			return err
+		}
+		publicData, err = jwt.MarshalPublicKeyPEM(public)
+		if err != nil {
+			return err
+		}
+	} else {
+		k, err := jwt.NewPrivateJWK(key)
+		if err != nil {
+			return err
+		}
+		k.Kid = *kid
+		privateData, err = marshalJSON(k)
+		if err != nil {
+			return err
+		}
+		publicData, err = marshalJSON(k.Public())
+		if err != nil {
+			return err
+		}
+	}
+
+	if *pub != "" {
+		err = os.WriteFile(*pub, publicData, 0644)
+		if err != nil {
+			return err
+		}
+	}
+	if *out != "" {
+		return os.WriteFile(*out, privateData, 0600)
+	}
+	_, err = stdout.Write(privateData)
+	return err
+}
+
+// loadPrivateKey parses a private key from PEM or JWK and returns it together with the key ID of the JWK
+func loadPrivateKey(path string) (crypto.PrivateKey, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	if isPEM(data) {
+		key, err := jwt.ParsePrivateKeyPEM(data)
+		return key, "", err
+	}
+	var k jwt.JWK
+	err = json.Unmarshal(data, &k)
+	if err != nil {
+		return nil, "", err
+	}
+	key, err := k.PrivateKey()
+	return key, k.Kid, err
+}
+
+// loadVerifier returns a Verifier using the public key parsed from PEM, JWK or JWKS
+// Single keys are used regardless of the key ID of the token while keys in a JWKS are selected by their key ID and tokens without a key ID are checked against all of them
+func loadVerifier(path string, opts ...jwt.VerifierOption) (*jwt.Verifier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if isPEM(data) {
+		key, err := jwt.ParsePublicKeyPEM(data)
+		if err != nil {
+			return nil, err
+		}
+		return jwt.NewVerifier(key, opts...)
+	}
+
+	var set struct {
+		Keys *[]jwt.JWK `json:"keys"`
+	}
+	err = json.Unmarshal(data, &set)
+	if err != nil {
+		return nil, err
+	}
+	if set.Keys == nil {
+		var k jwt.JWK
+		err = json.Unmarshal(data, &k)
+		if err != nil {
+			return nil, err
+		}
+		key, err := k.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		return jwt.NewVerifier(key, opts...)
+	}
+	ks := jwt.NewMemoryKeySet()
+	ks.SetTryAll(true)
+	hasDefault := false
+	for _, k := range *set.Keys {
+		key, err := k.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case k.Kid != "":
+			err = ks.Add(k.Kid, key)
+		case hasDefault:
+			err = errors.New("only a single key without key ID is supported")
+		default:
+			err = ks.SetDefault(key)
+			hasDefault = true
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return jwt.NewVerifierWithKeySet(ks, opts...)
+}
+
+func isPEM(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN"))
+}
+
+// marshalJSON returns v as indented JSON followed by a newline
+func marshalJSON(v interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
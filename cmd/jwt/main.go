@@ -0,0 +1,164 @@
+// Command jwt generates keys and signs, decodes and verifies JSON web tokens
+//
+// Usage:
+//
+//	jwt keygen [-format pem|jwk] [-kid id] [-out file] [-pub file]
+//	jwt sign -key file [-kid id] [-jku url] [-exp duration] [-iss issuer] [-sub subject] [-aud audience] [-claim name=value] [claims.json|-]
+//	jwt decode [token|-]
+//	jwt verify -key file [-alg alg] [-iss issuer] [-aud audience] [-leeway duration] [token|-]
+//
+// Keys may be supplied as PEM or JWK and verify additionally accepts a JWKS
+// Tokens are read from stdin when no argument or - is given, claims only when - is given
+//
+// The exit code indicates why a command failed:
+//
+//	1 any other error, e.g. a file could not be read
+//	2 invalid usage
+//	3 the token is malformed
+//	4 the signature is invalid, the key is unknown or the algorithm is not allowed
+//	5 the token has expired or is not valid, yet
+//	6 a claim is missing or does not have the expected value
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	jwt "github.com/fossoreslp/go-jwt-ed25519"
+)
+
+// Exit codes indicating why a command failed
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitMalformed
+	exitSignature
+	exitExpired
+	exitClaims
+)
+
+// command runs a subcommand with its arguments, reading input from stdin and writing results to stdout
+type command func(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error
+
+var commands = map[string]command{
+	"keygen": keygen,
+	"sign":   sign,
+	"decode": decode,
+	"verify": verify,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the subcommand named by the first argument and returns the exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: jwt keygen|sign|decode|verify [flags]")
+		return exitUsage
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "jwt: unknown command %s\nusage: jwt keygen|sign|decode|verify [flags]\n", args[0])
+		return exitUsage
+	}
+	fs := flag.NewFlagSet("jwt "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	err := cmd(fs, args[1:], stdin, stdout)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		// Errors returned by the flag set have already been printed
+		var u usageError
+		if !errors.As(err, &u) || u.msg != "" {
+			fmt.Fprintf(stderr, "jwt %s: %s\n", args[0], err.Error())
+		}
+	}
+	return exitCode(err)
+}
+
+// exitCode returns the exit code indicating the cause of err
+func exitCode(err error) int {
+	var u usageError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &u):
+		return exitUsage
+	case errors.Is(err, jwt.ErrMalformed):
+		return exitMalformed
+	case errors.Is(err, jwt.ErrSignatureInvalid), errors.Is(err, jwt.ErrUnknownKey), errors.Is(err, jwt.ErrAlgNotAllowed):
+		return exitSignature
+	case errors.Is(err, jwt.ErrExpired), errors.Is(err, jwt.ErrNotYetValid):
+		return exitExpired
+	case errors.Is(err, jwt.ErrClaimMissing), errors.Is(err, jwt.ErrClaimInvalid):
+		return exitClaims
+	}
+	return exitError
+}
+
+// usageError is returned when a command is used incorrectly
+// An empty message indicates the flag set has already reported the error
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+// parse parses args using fs and returns the remaining positional argument, if any
+func parse(fs *flag.FlagSet, args []string) (string, error) {
+	err := fs.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return "", err
+	}
+	if err != nil {
+		return "", usageError{}
+	}
+	switch fs.NArg() {
+	case 0:
+		return "", nil
+	case 1:
+		return fs.Arg(0), nil
+	}
+	return "", usageError{"too many arguments"}
+}
+
+// input returns the content of the file at path or stdin if path is empty or -
+func input(path string, stdin io.Reader) ([]byte, error) {
+	if path == "" || path == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// readToken returns the token passed as arg or read from stdin if arg is empty or -
+func readToken(arg string, stdin io.Reader) (string, error) {
+	if arg != "" && arg != "-" {
+		return arg, nil
+	}
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// stringList is a flag that may be passed multiple times
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
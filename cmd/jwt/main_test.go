@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// exec runs the command line args with stdin and returns the exit code and output
+func exec(t *testing.T, stdin string, args ...string) (int, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	if code != exitOK {
+		t.Logf("jwt %s: %s", strings.Join(args, " "), stderr.String())
+	}
+	return code, stdout.String()
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	key := filepath.Join(dir, "key.pem")
+	pub := filepath.Join(dir, "key.pub.pem")
+	jwk := filepath.Join(dir, "key.jwk")
+	jwkPub := filepath.Join(dir, "key.pub.jwk")
+	jwks := filepath.Join(dir, "keys.jwks")
+	if code, _ := exec(t, "", "keygen", "-out", key, "-pub", pub); code != exitOK {
+		t.Fatalf("keygen exited with %d", code)
+	}
+	if code, _ := exec(t, "", "keygen", "-format", "jwk", "-kid", "key", "-out", jwk, "-pub", jwkPub); code != exitOK {
+		t.Fatalf("keygen exited with %d", code)
+	}
+	data, err := os.ReadFile(jwkPub)
+	if err != nil {
+		t.Fatalf("Failed to read public key: %s", err.Error())
+	}
+	if bytes.Contains(data, []byte(`"d"`)) {
+		t.Error("keygen wrote private key to public key file")
+	}
+	err = os.WriteFile(jwks, append(append([]byte(`{"keys":[`), data...), ']', '}'), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write key set: %s", err.Error())
+	}
+
+	sign := func(stdin string, args ...string) string {
+		code, out := exec(t, stdin, append([]string{"sign"}, args...)...)
+		if code != exitOK {
+			t.Fatalf("sign exited with %d", code)
+		}
+		return strings.TrimSpace(out)
+	}
+	valid := sign(`{"name":"John Doe","admin":true}`, "-key", key, "-iss", "issuer", "-sub", "1234", "-exp", "1h", "-claim", "roles=[\"user\"]", "-")
+	expired := sign("", "-key", key, "-exp", "-1m")
+	withKeyID := sign("", "-key", jwk, "-aud", "service1", "-aud", "service2")
+
+	code, out := exec(t, "", "decode", valid)
+	if code != exitOK {
+		t.Fatalf("decode exited with %d", code)
+	}
+	var decoded struct {
+		Header  map[string]interface{}
+		Payload map[string]interface{}
+	}
+	err = json.Unmarshal([]byte(out), &decoded)
+	if err != nil {
+		t.Fatalf("decode output is not JSON: %s", err.Error())
+	}
+	if decoded.Header["alg"] != "EdDSA" || decoded.Payload["name"] != "John Doe" || decoded.Payload["sub"] != "1234" || decoded.Payload["iat"] == nil || len(decoded.Payload["roles"].([]interface{})) != 1 {
+		t.Errorf("decode output = %s", out)
+	}
+	code, out = exec(t, withKeyID+"\n", "decode")
+	if code != exitOK || !strings.Contains(out, `"kid": "key"`) || !strings.Contains(out, `"service2"`) {
+		t.Errorf("decode exited with %d, output = %s", code, out)
+	}
+
+	tests := []struct {
+		name  string
+		stdin string
+		args  []string
+		want  int
+	}{
+		{"Valid", "", []string{"verify", "-key", pub, "-iss", "issuer", valid}, exitOK},
+		{"ValidStdin", valid, []string{"verify", "-key", pub}, exitOK},
+		{"JWK", "", []string{"verify", "-key", jwkPub, "-aud", "service2", withKeyID}, exitOK},
+		{"JWKS", "", []string{"verify", "-key", jwks, withKeyID}, exitOK},
+		{"WrongKey", "", []string{"verify", "-key", jwkPub, valid}, exitSignature},
+		{"UnknownKey", "", []string{"verify", "-key", jwks, valid}, exitSignature},
+		{"Algorithm", "", []string{"verify", "-key", pub, "-alg", "ES256", valid}, exitSignature},
+		{"Expired", "", []string{"verify", "-key", pub, expired}, exitExpired},
+		{"ExpiredWithinLeeway", "", []string{"verify", "-key", pub, "-leeway", "2m", expired}, exitOK},
+		{"WrongIssuer", "", []string{"verify", "-key", pub, "-iss", "other", valid}, exitClaims},
+		{"Malformed", "", []string{"verify", "-key", pub, "test"}, exitMalformed},
+		{"DecodeMalformed", "", []string{"decode", "test"}, exitMalformed},
+		{"MissingKeyFile", "", []string{"verify", "-key", filepath.Join(dir, "missing"), valid}, exitError},
+		{"MissingKey", "", []string{"verify", valid}, exitUsage},
+		{"UnknownFlag", "", []string{"verify", "-unknown", valid}, exitUsage},
+		{"TooManyArguments", "", []string{"decode", valid, valid}, exitUsage},
+		{"KeyURLWithoutKeyID", "", []string{"sign", "-key", key, "-jku", "https://example.com/keys"}, exitError},
+		{"InvalidClaim", "", []string{"sign", "-key", key, "-claim", "test"}, exitUsage},
+		{"NullClaims", "null", []string{"sign", "-key", key, "-"}, exitError},
+		{"ArrayClaims", "[]", []string{"sign", "-key", key, "-"}, exitError},
+		{"StringClaims", `"x"`, []string{"sign", "-key", key, "-"}, exitError},
+		{"InvalidFormat", "", []string{"keygen", "-format", "der"}, exitUsage},
+		{"Help", "", []string{"keygen", "-h"}, exitOK},
+		{"UnknownCommand", "", []string{"test"}, exitUsage},
+		{"NoCommand", "", nil, exitUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _ := exec(t, tt.stdin, tt.args...); code != tt.want {
+				t.Errorf("jwt %s exited with %d, want %d", strings.Join(tt.args, " "), code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"strings"
+	"time"
+
+	jwt "github.com/fossoreslp/go-jwt-ed25519"
+)
+
+// sign signs the claims read from a JSON file or stdin, extended by the claims passed as flags, and writes the token to stdout
+func sign(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	keyFile := fs.String("key", "", "`file` containing the private key as PEM or JWK")
+	kid := fs.String("kid", "", "key `ID` inserted into the header, defaults to the key ID of a JWK")
+	jku := fs.String("jku", "", "key `URL` inserted into the header, requires a key ID")
+	exp := fs.Duration("exp", 0, "`duration` after which the token expires")
+	iat := fs.Bool("iat", true, "set the issue date to the current time")
+	iss := fs.String("iss", "", "`issuer` of the token")
+	sub := fs.String("sub", "", "`subject` of the token")
+	var aud, claims stringList
+	fs.Var(&aud, "aud", "`audience` of the token, may be repeated")
+	fs.Var(&claims, "claim", "additional claim as `name=value`, values are used as JSON if valid and as string otherwise, may be repeated")
+	arg, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if *keyFile == "" {
+		return usageError{"a key is required"}
+	}
+
+	// Claims are only read from stdin if requested explicitly, so flags alone are sufficient to create a token
+	content := make(map[string]interface{})
+	if arg != "" {
+		data, err := input(arg, stdin)
+		if err != nil {
+			return err
+		}
+		d := json.NewDecoder(bytes.NewReader(data))
+		d.UseNumber()
+		err = d.Decode(&content)
+		if err != nil {
+			return errors.New("claims are not a JSON object: " + err.Error())
+		}
+		// Decoding null succeeds but leaves no map to add claims to
+		if content == nil {
+			return errors.New("claims are not a JSON object")
+		}
+	}
+	now := time.Now()
+	if *iat {
+		content["iat"] = now.Unix()
+	}
+	if *exp != 0 {
+		content["exp"] = now.Add(*exp).Unix()
+	}
+	if *iss != "" {
+		content["iss"] = *iss
+	}
+	if *sub != "" {
+		content["sub"] = *sub
+	}
+	switch len(aud) {
+	case 0:
+	case 1:
+		content["aud"] = aud[0]
+	default:
+		content["aud"] = []string(aud)
+	}
+	for _, c := range claims {
+		name, value, ok := strings.Cut(c, "=")
+		if !ok || name == "" {
+			return usageError{"claims have to be passed as name=value"}
+		}
+		if json.Valid([]byte(value)) {
+			content[name] = json.RawMessage(value)
+		} else {
+			content[name] = value
+		}
+	}
+
+	key, keyID, err := loadPrivateKey(*keyFile)
+	if err != nil {
+		return err
+	}
+	if *kid != "" {
+		keyID = *kid
+	}
+	var s *jwt.Signer
+	switch {
+	case *jku != "":
+		s, err = jwt.NewSignerWithKeyIDAndKeyURL(key, keyID, *jku)
+	case keyID != "":
+		s, err = jwt.NewSignerWithKeyID(key, keyID)
+	default:
+		s, err = jwt.NewSigner(key)
+	}
+	if err != nil {
+		return err
+	}
+	token, err := s.Sign(content)
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(append(token, '\n'))
+	return err
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+
+	jwt "github.com/fossoreslp/go-jwt-ed25519"
+)
+
+// verify verifies a token and writes its payload to stdout if it is valid
+func verify(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	keyFile := fs.String("key", "", "`file` containing the public key as PEM, JWK or JWKS")
+	leeway := fs.Duration("leeway", 0, "`duration` exp, nbf and iat may be off by")
+	var algs, iss, aud stringList
+	fs.Var(&algs, "alg", "accepted `algorithm`, may be repeated")
+	fs.Var(&iss, "iss", "accepted `issuer`, may be repeated")
+	fs.Var(&aud, "aud", "accepted `audience`, may be repeated")
+	arg, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if *keyFile == "" {
+		return usageError{"a key is required"}
+	}
+	token, err := readToken(arg, stdin)
+	if err != nil {
+		return err
+	}
+
+	opts := []jwt.VerifierOption{jwt.WithLeeway(*leeway)}
+	if len(algs) > 0 {
+		opts = append(opts, jwt.WithAlgorithms(algs...))
+	}
+	if len(iss) > 0 {
+		opts = append(opts, jwt.WithIssuer(iss...))
+	}
+	if len(aud) > 0 {
+		opts = append(opts, jwt.WithAudience(aud...))
+	}
+	v, err := loadVerifier(*keyFile, opts...)
+	if err != nil {
+		return err
+	}
+	t, err := v.VerifyToken(token)
+	if err != nil {
+		return err
+	}
+	var payload json.RawMessage
+	err = t.Unmarshal(&payload)
+	if err != nil {
+		return err
+	}
+	out, err := marshalJSON(payload)
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(out)
+	return err
+}
@@ -0,0 +1,17 @@
+package jwt
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the verified token t
+func NewContext(ctx context.Context, t VerifiedToken) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the verified token stored in ctx by NewContext
+// The second return value is false if ctx does not carry a token
+func FromContext(ctx context.Context) (VerifiedToken, bool) {
+	t, ok := ctx.Value(contextKey{}).(VerifiedToken)
+	return t, ok
+}
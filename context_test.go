@@ -0,0 +1,20 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("FromContext() found token in empty context")
+	}
+	tok := VerifiedToken{header: Header{Typ: "JWT", Alg: "EdDSA", Kid: "key"}, payload: []byte(`{"sub":"1234"}`)}
+	got, ok := FromContext(NewContext(context.Background(), tok))
+	if !ok {
+		t.Fatal("FromContext() did not find token")
+	}
+	if got.Header().Kid != "key" || got.RegisteredClaims().Subject != "1234" {
+		t.Errorf("FromContext() = %+v, want %+v", got, tok)
+	}
+}
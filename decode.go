@@ -1,53 +1,38 @@
 package jwt
 
 import (
-	"encoding/base64"
 	"encoding/json"
 	"errors"
-	"strings"
 )
 
 // Decode decodes a string to a JWT and checks it for validity
+// Errors caused by the token itself match ErrMalformed
 func Decode(token string) (data JWT, err error) {
-	// Split the JWT into it's sections (header, content, hash)
-	sections := strings.Split(token, ".")
-	if len(sections) != 3 {
-		err = errors.New("invalid token")
-		return
-	}
-
-	// Decode first section to header
-	headerData, err := base64.RawURLEncoding.DecodeString(sections[0])
+	header, content, hash, raw, err := decodeSections(token)
 	if err != nil {
 		return
 	}
-	err = json.Unmarshal(headerData, &data.Header)
+	data.Header = header
+	err = json.Unmarshal(content, &data.Content)
 	if err != nil {
+		err = malformed(err)
 		return
 	}
-	if data.Header.Typ != "JWT" {
-		err = errors.New("header suggests token is not a JWT")
-		return
-	}
+	data.Hash = hash
+	data.raw = raw
+	return
+}
 
-	// Decode second section to content
-	contentData, err := base64.RawURLEncoding.DecodeString(sections[1])
+// decodeSections decodes a token as JWS and checks that it is a JWT while leaving the content as JSON for the caller to unmarshal
+// The encoded header and content are returned as raw as the hash has to be verified against them
+func decodeSections(token string) (header Header, content, hash, raw []byte, err error) {
+	j, err := DecodeJWS(token)
 	if err != nil {
 		return
 	}
-	err = json.Unmarshal(contentData, &data.Content)
-	if err != nil {
+	if j.Header.Typ != "JWT" {
+		err = malformed(errors.New("header suggests token is not a JWT"))
 		return
 	}
-
-	// Decode third section to hash
-	data.Hash, err = base64.RawURLEncoding.DecodeString(sections[2])
-	if err != nil {
-		return
-	}
-	if data.Hash == nil || len(data.Hash) < 1 || sections[2] == "" {
-		err = errors.New("hash may not be empty")
-	}
-
-	return
+	return j.Header, j.Payload, j.Signature, j.raw, nil
 }
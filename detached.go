@@ -0,0 +1,113 @@
+package jwt
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// SignDetached returns a JWS of payload with an empty payload section as defined in RFC 7515 Appendix F
+// The payload has to be transmitted separately and supplied to VerifyDetached
+func (s *Signer) SignDetached(payload []byte) ([]byte, error) {
+	return s.signDetached(payload, true)
+}
+
+// SignDetachedUnencoded behaves like SignDetached but signs the payload as is instead of its base64 encoding as defined in RFC 7797
+// The header contains b64 set to false and lists it as critical, so recipients that do not support it reject the signature
+func (s *Signer) SignDetachedUnencoded(payload []byte) ([]byte, error) {
+	return s.signDetached(payload, false)
+}
+
+func (s *Signer) signDetached(payload []byte, encoded bool) ([]byte, error) {
+	h := Header{Typ: "JOSE", Alg: s.method.Alg(), Kid: s.kid, Jku: s.jku}
+	if !encoded {
+		h.B64 = new(bool)
+		h.Crit = []string{"b64"}
+	}
+	header := encodeHeader(h)
+	hash, err := s.method.Sign(signingInput(header, payload, encoded), s.key)
+	if err != nil {
+		return nil, err
+	}
+	return join(header, nil, b64encode(hash)), nil
+}
+
+// VerifyDetached checks the signature of a JWS with a detached payload against payload using key and returns its header
+// Both base64 encoded and unencoded payloads are supported, the form is determined using the b64 header
+func VerifyDetached(token string, payload []byte, key crypto.PublicKey) (Header, error) {
+	err := checkPublicKey(key)
+	if err != nil {
+		return Header{}, err
+	}
+	return verifyDetached(token, payload, singleKey{key})
+}
+
+// VerifyDetachedWithKeySet behaves like VerifyDetached but uses the keys provided by ks for the header of the token
+func VerifyDetachedWithKeySet(token string, payload []byte, ks KeySet) (Header, error) {
+	return verifyDetached(token, payload, ks)
+}
+
+func verifyDetached(token string, payload []byte, ks KeySet) (Header, error) {
+	sections := strings.Split(token, ".")
+	if len(sections) != 3 {
+		return Header{}, malformed(errors.New("invalid token"))
+	}
+	if sections[1] != "" {
+		return Header{}, malformed(errors.New("payload is not detached"))
+	}
+	headerData, err := base64.RawURLEncoding.DecodeString(sections[0])
+	if err != nil {
+		return Header{}, malformed(err)
+	}
+	var h Header
+	err = json.Unmarshal(headerData, &h)
+	if err != nil {
+		return Header{}, malformed(err)
+	}
+	encoded, err := checkCritical(h)
+	if err != nil {
+		return Header{}, err
+	}
+	hash, err := base64.RawURLEncoding.DecodeString(sections[2])
+	if err != nil {
+		return Header{}, malformed(err)
+	}
+	if len(hash) < 1 {
+		return Header{}, malformed(errors.New("hash may not be empty"))
+	}
+
+	// The payload is inserted into the signing input the same way it would have appeared in the token
+	j := JWS{Header: h, Payload: payload, Signature: hash, raw: signingInput([]byte(sections[0]), payload, encoded)}
+	err = j.VerifyWithKeySet(ks)
+	if err != nil {
+		return Header{}, err
+	}
+	return h, nil
+}
+
+// checkCritical returns an error if the header contains critical parameters that are not supported and reports whether the payload is base64 encoded
+func checkCritical(h Header) (encoded bool, err error) {
+	for _, c := range h.Crit {
+		if c != "b64" {
+			return false, malformed(errors.New("critical header parameter " + c + " is not supported"))
+		}
+	}
+	if h.B64 != nil && !contains(h.Crit, "b64") {
+		return false, malformed(errors.New("header parameter b64 has to be critical"))
+	}
+	return h.B64 == nil || *h.B64, nil
+}
+
+// signingInput returns the data signed for a JWS with the encoded header and payload
+// The payload is base64 encoded unless encoded is false as defined in RFC 7797
+func signingInput(header, payload []byte, encoded bool) []byte {
+	if encoded {
+		payload = b64encode(payload)
+	}
+	data := make([]byte, 0, len(header)+1+len(payload))
+	data = append(data, header...)
+	data = append(data, '.')
+	return append(data, payload...)
+}
@@ -0,0 +1,115 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+// Key taken from RFC 7515 Appendix A.1 as used by the examples in RFC 7797 Section 4
+const rfc7797Key = "AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"
+
+func TestVerifyDetached_RFC7797(t *testing.T) {
+	k, err := base64.RawURLEncoding.DecodeString(rfc7797Key)
+	if err != nil {
+		t.Fatalf("Failed to decode key: %s", err.Error())
+	}
+	key := HMACKey(k)
+	payload := []byte("$.02")
+	tests := []struct {
+		name    string
+		token   string
+		payload []byte
+		wantErr bool
+	}{
+		{"Encoded", "eyJhbGciOiJIUzI1NiJ9..5mvfOroL-g7HyqJoozehmsaqmvTYGEq5jTI1gVvoEoQ", payload, false},
+		{"Unencoded", "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY", payload, false},
+		{"EncodedWrongPayload", "eyJhbGciOiJIUzI1NiJ9..5mvfOroL-g7HyqJoozehmsaqmvTYGEq5jTI1gVvoEoQ", []byte("$.03"), true},
+		{"UnencodedWrongPayload", "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY", []byte("$.03"), true},
+		{"Attached", "eyJhbGciOiJIUzI1NiJ9.JC4wMg.5mvfOroL-g7HyqJoozehmsaqmvTYGEq5jTI1gVvoEoQ", payload, true},
+		// Header {"alg":"HS256","b64":false} without crit
+		{"NotCritical", "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2V9..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY", payload, true},
+		// Header {"alg":"HS256","crit":["exp"]}
+		{"UnsupportedCritical", "eyJhbGciOiJIUzI1NiIsImNyaXQiOlsiZXhwIl19..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY", payload, true},
+		{"EmptyHash", "eyJhbGciOiJIUzI1NiJ9..", payload, true},
+		{"InvalidToken", "eyJhbGciOiJIUzI1NiJ9.", payload, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := VerifyDetached(tt.token, tt.payload, key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyDetached() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && h.Alg != "HS256" {
+				t.Errorf("VerifyDetached() returned header with algorithm %s, want HS256", h.Alg)
+			}
+		})
+	}
+}
+
+func TestSigner_SignDetached(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSignerWithKeyID(key, "webhook")
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	payload := []byte(`{"event":"push","ref":"refs/heads/main"}`)
+	for _, sign := range []func([]byte) ([]byte, error){s.SignDetached, s.SignDetachedUnencoded} {
+		enc, err := sign(payload)
+		if err != nil {
+			t.Fatalf("Failed to sign payload: %s", err.Error())
+		}
+		sections := strings.Split(string(enc), ".")
+		if len(sections) != 3 || sections[1] != "" {
+			t.Fatalf("Signed token %s does not have a detached payload", enc)
+		}
+		h, err := VerifyDetached(string(enc), payload, public)
+		if err != nil {
+			t.Errorf("Failed to verify detached payload: %s", err.Error())
+		}
+		if h.Kid != "webhook" || h.Alg != "EdDSA" {
+			t.Errorf("VerifyDetached() returned header %+v", h)
+		}
+		ks := NewMemoryKeySet()
+		if err := ks.Add("webhook", public); err != nil {
+			t.Fatalf("Failed to add key: %s", err.Error())
+		}
+		if _, err := VerifyDetachedWithKeySet(string(enc), payload, ks); err != nil {
+			t.Errorf("Failed to verify detached payload with key set: %s", err.Error())
+		}
+		if _, err := VerifyDetached(string(enc), []byte(`{"event":"push","ref":"refs/heads/evil"}`), public); !errors.Is(err, ErrSignatureInvalid) {
+			t.Errorf("VerifyDetached() error = %v for modified payload, want %v", err, ErrSignatureInvalid)
+		}
+		if _, err := Decode(string(enc)); err == nil {
+			t.Error("Decode() accepted detached JWS")
+		}
+	}
+
+	// Unencoded payloads are signed as is
+	enc, err := s.SignDetachedUnencoded(payload)
+	if err != nil {
+		t.Fatalf("Failed to sign payload: %s", err.Error())
+	}
+	header := string(enc[:strings.IndexByte(string(enc), '.')])
+	hash, err := base64.RawURLEncoding.DecodeString(string(enc[strings.LastIndexByte(string(enc), '.')+1:]))
+	if err != nil {
+		t.Fatalf("Failed to decode hash: %s", err.Error())
+	}
+	if !ed25519.Verify(public, []byte(header+"."+string(payload)), hash) {
+		t.Error("Signature of unencoded payload does not match payload as is")
+	}
+}
+
+func TestDecode_Critical(t *testing.T) {
+	// Header {"typ":"JWT","alg":"EdDSA","crit":["b64"],"b64":false}
+	if _, err := Decode("eyJ0eXAiOiJKV1QiLCJhbGciOiJFZERTQSIsImNyaXQiOlsiYjY0Il0sImI2NCI6ZmFsc2V9.eyJzdWIiOiJ0ZXN0In0.AA"); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Decode() error = %v, want %v", err, ErrMalformed)
+	}
+}
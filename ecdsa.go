@@ -0,0 +1,74 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+)
+
+// SigningMethodES256 implements ECDSA using P-256 and SHA-256 as defined in RFC 7518
+var SigningMethodES256 SigningMethod = signingMethodECDSA{"ES256", elliptic.P256(), sha256.New}
+
+// SigningMethodES384 implements ECDSA using P-384 and SHA-384 as defined in RFC 7518
+var SigningMethodES384 SigningMethod = signingMethodECDSA{"ES384", elliptic.P384(), sha512.New384}
+
+type signingMethodECDSA struct {
+	alg   string
+	curve elliptic.Curve
+	hash  func() hash.Hash
+}
+
+func (m signingMethodECDSA) Alg() string {
+	return m.alg
+}
+
+func (m signingMethodECDSA) digest(data []byte) []byte {
+	h := m.hash()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func (m signingMethodECDSA) size() int {
+	return (m.curve.Params().BitSize + 7) / 8
+}
+
+func (m signingMethodECDSA) Sign(data []byte, key crypto.PrivateKey) ([]byte, error) {
+	k, ok := key.(*ecdsa.PrivateKey)
+	if !ok || k == nil || k.Curve != m.curve {
+		return nil, errors.New("key is not a valid " + m.alg + " private key")
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, k, m.digest(data))
+	if err != nil {
+		return nil, err
+	}
+	// The signature is the concatenation of r and s, each padded to the size of the curve
+	size := m.size()
+	out := make([]byte, 2*size)
+	r.FillBytes(out[:size])
+	s.FillBytes(out[size:])
+	return out, nil
+}
+
+func (m signingMethodECDSA) Verify(data, signature []byte, key crypto.PublicKey) error {
+	k, ok := key.(*ecdsa.PublicKey)
+	if !ok || k == nil || k.Curve != m.curve {
+		return fmt.Errorf("%w for algorithm %s", ErrInvalidKey, m.alg)
+	}
+	size := m.size()
+	if len(signature) != 2*size {
+		return ErrSignatureInvalid
+	}
+	r := new(big.Int).SetBytes(signature[:size])
+	s := new(big.Int).SetBytes(signature[size:])
+	if !ecdsa.Verify(k, m.digest(data), r, s) {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
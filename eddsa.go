@@ -0,0 +1,55 @@
+package jwt
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+
+	"github.com/cloudflare/circl/sign/ed448"
+	"golang.org/x/crypto/ed25519"
+)
+
+// SigningMethodEdDSA implements EdDSA as defined in RFC 8037 using Ed25519 or Ed448 keys with the curve determined by the type of the key
+var SigningMethodEdDSA SigningMethod = signingMethodEdDSA{}
+
+type signingMethodEdDSA struct{}
+
+func (signingMethodEdDSA) Alg() string {
+	return "EdDSA"
+}
+
+func (signingMethodEdDSA) Sign(data []byte, key crypto.PrivateKey) ([]byte, error) {
+	switch k := edwardsPrivateKey(key).(type) {
+	case ed25519.PrivateKey:
+		if len(k) == ed25519.PrivateKeySize {
+			return ed25519.Sign(k, data), nil
+		}
+	case ed448.PrivateKey:
+		if len(k) == ed448.PrivateKeySize {
+			return ed448.Sign(k, data, ""), nil
+		}
+	}
+	return nil, errors.New("key is not a valid EdDSA private key")
+}
+
+func (signingMethodEdDSA) Verify(data, signature []byte, key crypto.PublicKey) error {
+	var valid bool
+	switch k := edwardsPublicKey(key).(type) {
+	case ed25519.PublicKey:
+		if len(k) != ed25519.PublicKeySize {
+			return ErrInvalidKey
+		}
+		valid = ed25519.Verify(k, data, signature)
+	case ed448.PublicKey:
+		if len(k) != ed448.PublicKeySize {
+			return ErrInvalidKey
+		}
+		valid = ed448.Verify(k, data, signature, "")
+	default:
+		return fmt.Errorf("%w for algorithm EdDSA", ErrInvalidKey)
+	}
+	if !valid {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cloudflare/circl/sign/ed448"
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestSigningMethodEdDSA_Sign(t *testing.T) {
+	// Test vector taken from RFC 8032 Section 7.4 (blank message)
+	seed, _ := hex.DecodeString("6c82a562cb808d10d632be89c8513ebf6c929f34ddfa8c9f63c9960ef6e348a3528c8a3fcc2f044e39a3fc5b94492f8f032e7549a20098f95b")
+	public, _ := hex.DecodeString("5fd7449b59b461fd2ce787ec616ad46a1da1342485a70e1f8a0ea75d80e96778edf124769b46c7061bd6783df1e50f6cd1fa1abeafe8256180")
+	signature := "533a37f6bbe457251f023c0d88f976ae2dfb504a843e34d2074fd823d41a591f2b233f034f628281f2fd7a22ddd47d7828c59bd0a21bfd3980ff0d2028d4b18a9df63e006c5d1c2d345b925d8dc00b4104852db99ac5c7cdda8530a113a0f4dbb61149f05a7363268c71d95808ff2e652600"
+	got, err := SigningMethodEdDSA.Sign([]byte{}, ed448.NewKeyFromSeed(seed))
+	if err != nil {
+		t.Fatalf("SigningMethodEdDSA.Sign() error = %v", err)
+	}
+	if hex.EncodeToString(got) != signature {
+		t.Errorf("SigningMethodEdDSA.Sign() = %x, want %s", got, signature)
+	}
+	if err := SigningMethodEdDSA.Verify([]byte{}, got, ed448.PublicKey(public)); err != nil {
+		t.Errorf("SigningMethodEdDSA.Verify() error = %v", err)
+	}
+
+	if _, err := SigningMethodEdDSA.Sign([]byte{}, ed448.PrivateKey("test")); err == nil {
+		t.Error("SigningMethodEdDSA.Sign() accepted invalid Ed448 key")
+	}
+	if _, err := SigningMethodEdDSA.Sign([]byte{}, HMACKey("test")); err == nil {
+		t.Error("SigningMethodEdDSA.Sign() accepted key of unsupported type")
+	}
+}
+
+func TestSigningMethodEdDSA_Verify(t *testing.T) {
+	public25519, private25519, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	public448, private448, err := ed448.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	data := []byte("test")
+	sig25519, _ := SigningMethodEdDSA.Sign(data, private25519)
+	sig448, _ := SigningMethodEdDSA.Sign(data, private448)
+	tests := []struct {
+		name      string
+		key       crypto.PublicKey
+		signature []byte
+		wantErr   bool
+	}{
+		{"Ed25519", public25519, sig25519, false},
+		{"Ed448", public448, sig448, false},
+		{"Ed25519KeyEd448Signature", public25519, sig448, true},
+		{"Ed448KeyEd25519Signature", public448, sig25519, true},
+		{"InvalidEd25519Key", ed25519.PublicKey("test"), sig25519, true},
+		{"InvalidEd448Key", ed448.PublicKey("test"), sig448, true},
+		{"UnsupportedKey", []byte("test"), sig25519, true},
+		{"HMACKey", HMACKey(public25519), sig25519, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SigningMethodEdDSA.Verify(data, tt.signature, tt.key); (err != nil) != tt.wantErr {
+				t.Errorf("SigningMethodEdDSA.Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEd448(t *testing.T) {
+	public, private, err := ed448.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	public25519, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSignerWithKeyID(private, "ed448")
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	enc, err := s.Sign(RegisteredClaims{Subject: "test"})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+	dec, err := Decode(string(enc))
+	if err != nil {
+		t.Fatalf("Failed to decode token: %s", err.Error())
+	}
+	if dec.Header.Alg != "EdDSA" {
+		t.Errorf("Token signed using Ed448 has algorithm %s, want EdDSA", dec.Header.Alg)
+	}
+	if err := dec.Validate(public); err != nil {
+		t.Errorf("Failed to validate token signed using Ed448: %s", err.Error())
+	}
+	if err := dec.Validate(public25519); err == nil {
+		t.Error("Token signed using Ed448 was validated using Ed25519 key")
+	}
+
+	// The curve of keys in key sets is chosen using the curve of the JWK
+	k, err := NewPrivateJWK(private)
+	if err != nil {
+		t.Fatalf("Failed to create JWK: %s", err.Error())
+	}
+	if k.Crv != "Ed448" {
+		t.Errorf("JWK for Ed448 key has curve %s", k.Crv)
+	}
+	if _, err := k.Thumbprint(); err != nil {
+		t.Errorf("Failed to compute thumbprint of Ed448 key: %s", err.Error())
+	}
+	parsed, err := k.PrivateKey()
+	if err != nil || !private.Equal(parsed) {
+		t.Errorf("JWK.PrivateKey() = %v, %v, want original key", parsed, err)
+	}
+	key, err := k.Public().PublicKey()
+	if err != nil {
+		t.Fatalf("Failed to get public key from JWK: %s", err.Error())
+	}
+	ks := NewMemoryKeySet()
+	if err := ks.Add("ed448", key); err != nil {
+		t.Fatalf("Failed to add key: %s", err.Error())
+	}
+	if err := dec.VerifyWithKeySet(ks); err != nil {
+		t.Errorf("Failed to verify token signed using Ed448 with key set: %s", err.Error())
+	}
+
+	if _, err := (JWK{Kty: "OKP", Crv: "Ed448", X: k.X, D: rfc8037D}).PrivateKey(); err == nil {
+		t.Error("JWK.PrivateKey() accepted Ed25519 seed for Ed448 key")
+	}
+	if _, err := (JWK{Kty: "OKP", Crv: "Ed448", X: rfc8037X}).PublicKey(); err == nil {
+		t.Error("JWK.PublicKey() accepted Ed25519 key for Ed448 curve")
+	}
+}
+
+func TestEdDSA_ByteSliceKeys(t *testing.T) {
+	public25519, private25519, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	public448, private448, err := ed448.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	tests := []struct {
+		name    string
+		public  []byte
+		private []byte
+	}{
+		{"Ed25519", public25519, private25519},
+		{"Ed448", public448, private448},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSigner(tt.private)
+			if err != nil {
+				t.Fatalf("Failed to create signer: %s", err.Error())
+			}
+			enc, err := s.Sign(RegisteredClaims{Subject: "test"})
+			if err != nil {
+				t.Fatalf("Failed to sign token: %s", err.Error())
+			}
+			dec, err := Decode(string(enc))
+			if err != nil {
+				t.Fatalf("Failed to decode token: %s", err.Error())
+			}
+			if err := dec.Validate(tt.public); err != nil {
+				t.Errorf("Failed to validate token using key of type []byte: %s", err.Error())
+			}
+			v, err := NewVerifier(tt.public)
+			if err != nil {
+				t.Fatalf("Failed to create verifier: %s", err.Error())
+			}
+			if _, err := v.Verify(string(enc)); err != nil {
+				t.Errorf("Verifier.Verify() failed using key of type []byte: %s", err.Error())
+			}
+		})
+	}
+
+	// Byte slices of other sizes are never valid keys
+	if err := checkPublicKey([]byte("test")); err == nil {
+		t.Error("checkPublicKey() accepted byte slice of invalid size")
+	}
+}
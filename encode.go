@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"reflect"
-
-	"golang.org/x/crypto/ed25519"
 )
 
 // New returns a new JWT containing content
@@ -15,7 +13,7 @@ func New(content interface{}) (JWT, error) {
 	if (reflect.TypeOf(content).Kind() != reflect.Struct && reflect.TypeOf(content).Kind() != reflect.Map) || (reflect.TypeOf(content).Kind() == reflect.Map && reflect.TypeOf(content).Key().Kind() != reflect.String) {
 		return JWT{}, errors.New("content has to be map[string] or a struct")
 	}
-	return JWT{Header{Alg: "EdDSA", Typ: "JWT"}, content, nil}, nil
+	return JWT{Header: Header{Alg: "EdDSA", Typ: "JWT"}, Content: content}, nil
 }
 
 // NewWithKeyID returns a new JWT containing content with key ID inserted into the header
@@ -48,19 +46,13 @@ func NewWithKeyIDAndKeyURL(content interface{}, keyID, keyURL string) (out JWT,
 	return
 }
 
-// Encode a JWT to a byte slice
-func (t *JWT) Encode() (result []byte, err error) {
-	if !setup {
+// Encode a JWT to a byte slice using the private key provided to Setup
+func (t *JWT) Encode() ([]byte, error) {
+	s := getDefaultSigner()
+	if s == nil {
 		return nil, errors.New("call setup with private key first")
 	}
-	content, err := encode(t.Content)
-	if err != nil {
-		return
-	}
-	header := encodeHeader(t.Header)
-	hash := b64encode(ed25519.Sign(privateKey, join(header, content)))
-	result = join(header, content, hash)
-	return
+	return s.Encode(t)
 }
 
 func b64encode(data []byte) []byte {
@@ -79,7 +71,7 @@ func encode(data interface{}) (out []byte, err error) {
 }
 
 func encodeHeader(h Header) []byte {
-	enc, _ := encode(h) // Error is safe to ignore as encoding a struct containing only strings can't fail
+	enc, _ := encode(h) // Error is safe to ignore as encoding a struct containing only strings and booleans can't fail
 	return enc
 }
 
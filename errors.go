@@ -0,0 +1,113 @@
+package jwt
+
+import (
+	"errors"
+	"time"
+)
+
+// Errors returned when decoding or validating a token
+// Errors carrying additional details match these using errors.Is, so the cause of a failure can be determined without comparing messages
+var (
+	ErrMalformed        = errors.New("jwt is malformed")
+	ErrAlgNotAllowed    = errors.New("algorithm is not allowed")
+	ErrInvalidKey       = errors.New("key is not a valid public key")
+	ErrUnknownKey       = errors.New("unknown key ID")
+	ErrSignatureInvalid = errors.New("hash does not match content")
+	ErrDecryption       = errors.New("jwe could not be decrypted")
+	ErrExpired          = errors.New("jwt has expired")
+	ErrNotYetValid      = errors.New("jwt is not valid, yet")
+	ErrClaimMissing     = errors.New("required claim is missing")
+	ErrClaimInvalid     = errors.New("claim does not have the expected value")
+	ErrReplayed         = errors.New("jwt has already been used")
+	ErrReplayStoreFull  = errors.New("replay store is full")
+)
+
+// MalformedError is returned when a token cannot be decoded and matches ErrMalformed
+type MalformedError struct {
+	Err error // Reason the token could not be decoded
+}
+
+func (e *MalformedError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the reason the token could not be decoded
+func (e *MalformedError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrMalformed
+func (e *MalformedError) Is(target error) bool {
+	return target == ErrMalformed
+}
+
+// malformed wraps err in a MalformedError unless it is nil
+func malformed(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &MalformedError{err}
+}
+
+// AlgorithmError is returned when the algorithm of a token is not allowed or not supported and matches ErrAlgNotAllowed
+type AlgorithmError struct {
+	Alg         string // Algorithm from the header of the token
+	Unsupported bool   // Whether no signing method is registered for the algorithm
+}
+
+func (e *AlgorithmError) Error() string {
+	if e.Unsupported {
+		return "algorithm " + e.Alg + " is not supported"
+	}
+	return "algorithm " + e.Alg + " is not allowed"
+}
+
+// Is reports whether target is ErrAlgNotAllowed
+func (e *AlgorithmError) Is(target error) bool {
+	return target == ErrAlgNotAllowed
+}
+
+// ExpiredError is returned when a token has expired and matches ErrExpired
+// Tokens exceeding the maximum age of a Verifier are considered expired at the end of that age
+type ExpiredError struct {
+	ExpiresAt time.Time
+}
+
+func (e *ExpiredError) Error() string {
+	return ErrExpired.Error()
+}
+
+// Is reports whether target is ErrExpired
+func (e *ExpiredError) Is(target error) bool {
+	return target == ErrExpired
+}
+
+// NotYetValidError is returned when a token is used before it becomes valid and matches ErrNotYetValid
+type NotYetValidError struct {
+	NotBefore time.Time
+}
+
+func (e *NotYetValidError) Error() string {
+	return ErrNotYetValid.Error()
+}
+
+// Is reports whether target is ErrNotYetValid
+func (e *NotYetValidError) Is(target error) bool {
+	return target == ErrNotYetValid
+}
+
+// ClaimError is returned when a claim is missing or does not have the expected value
+// It wraps ErrClaimMissing or ErrClaimInvalid
+type ClaimError struct {
+	Claim string // Name of the claim
+	Err   error
+}
+
+func (e *ClaimError) Error() string {
+	return e.Err.Error() + ": " + e.Claim
+}
+
+// Unwrap returns ErrClaimMissing or ErrClaimInvalid
+func (e *ClaimError) Unwrap() error {
+	return e.Err
+}
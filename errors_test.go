@@ -0,0 +1,203 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestErrors(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	wrongKey, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	sign := func(content interface{}) string {
+		enc, err := s.Sign(content)
+		if err != nil {
+			t.Fatalf("Failed to sign token: %s", err.Error())
+		}
+		return string(enc)
+	}
+	hmacSigner, err := NewSigner(HMACKey(public))
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	forged, err := hmacSigner.Sign(RegisteredClaims{Subject: "admin"})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+	v, err := NewVerifier(public, WithAlgorithms("EdDSA"), WithIssuer("issuer"), WithRequiredClaims("sub"))
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %s", err.Error())
+	}
+	ks := NewMemoryKeySet()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	tests := []struct {
+		name   string
+		verify func() error
+		want   error
+	}{
+		{"Malformed", func() error { _, err := Decode("invalid"); return err }, ErrMalformed},
+		{"MalformedBase64", func() error { _, err := Decode("eyJ0eXAiOiJKV1QiLCJhbGciOiJFZERTQSJ9.!.AA"); return err }, ErrMalformed},
+		{"AlgorithmNone", func() error {
+			dec, err := Decode("eyJ0eXAiOiJKV1QiLCJhbGciOiJub25lIn0.eyJzdWIiOiJhZG1pbiJ9.AA")
+			if err != nil {
+				return err
+			}
+			return dec.Validate(public)
+		}, ErrAlgNotAllowed},
+		{"AlgorithmConfusion", func() error { _, err := v.Verify(string(forged)); return err }, ErrAlgNotAllowed},
+		{"AlgorithmConfusionWithoutVerifier", func() error {
+			dec, err := Decode(string(forged))
+			if err != nil {
+				return err
+			}
+			return dec.Validate(public)
+		}, ErrInvalidKey},
+		{"SignatureInvalid", func() error {
+			dec, err := Decode(sign(RegisteredClaims{}))
+			if err != nil {
+				return err
+			}
+			return dec.Validate(wrongKey)
+		}, ErrSignatureInvalid},
+		{"UnknownKey", func() error {
+			dec, err := Decode(sign(RegisteredClaims{}))
+			if err != nil {
+				return err
+			}
+			dec.Header.Kid = "unknown"
+			return dec.VerifyWithKeySet(ks)
+		}, ErrUnknownKey},
+		{"Expired", func() error {
+			_, err := v.Verify(sign(RegisteredClaims{Issuer: "issuer", Subject: "test", ExpiresAt: NewNumericDate(now.Add(-time.Minute))}))
+			return err
+		}, ErrExpired},
+		{"NotYetValid", func() error {
+			_, err := v.Verify(sign(RegisteredClaims{Issuer: "issuer", Subject: "test", NotBefore: NewNumericDate(now.Add(time.Minute))}))
+			return err
+		}, ErrNotYetValid},
+		{"ClaimMissing", func() error { _, err := v.Verify(sign(RegisteredClaims{Issuer: "issuer"})); return err }, ErrClaimMissing},
+		{"ClaimInvalid", func() error { _, err := v.Verify(sign(RegisteredClaims{Issuer: "other", Subject: "test"})); return err }, ErrClaimInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.verify()
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrors_Details(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	v, err := NewVerifier(s.Public(), WithAlgorithms("ES256"), WithMaxAge(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %s", err.Error())
+	}
+	exp := time.Now().Add(-time.Minute).Truncate(time.Second)
+	enc, err := s.Sign(RegisteredClaims{ExpiresAt: NewNumericDate(exp)})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+
+	_, err = v.Verify(string(enc))
+	var algErr *AlgorithmError
+	if !errors.As(err, &algErr) || algErr.Alg != "EdDSA" || algErr.Unsupported {
+		t.Errorf("Verifier.Verify() error = %v, want AlgorithmError for EdDSA", err)
+	}
+
+	dec, err := Decode(string(enc))
+	if err != nil {
+		t.Fatalf("Failed to decode token: %s", err.Error())
+	}
+	err = dec.Validate(s.Public())
+	var expErr *ExpiredError
+	if !errors.As(err, &expErr) || !expErr.ExpiresAt.Equal(exp) {
+		t.Errorf("JWT.Validate() error = %v, want ExpiredError at %s", err, exp)
+	}
+	if err.Error() != "jwt has expired" {
+		t.Errorf("JWT.Validate() error message = %s, want jwt has expired", err.Error())
+	}
+
+	dec.Header.Alg = "HS512"
+	err = dec.Validate(s.Public())
+	if !errors.As(err, &algErr) || algErr.Alg != "HS512" || !algErr.Unsupported {
+		t.Errorf("JWT.Validate() error = %v, want unsupported AlgorithmError for HS512", err)
+	}
+
+	enc, err = s.Sign(RegisteredClaims{Subject: "test"})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+	v, err = NewVerifier(s.Public(), WithMaxAge(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %s", err.Error())
+	}
+	_, err = v.Verify(string(enc))
+	var claimErr *ClaimError
+	if !errors.As(err, &claimErr) || claimErr.Claim != "iat" || !errors.Is(err, ErrClaimMissing) {
+		t.Errorf("Verifier.Verify() error = %v, want missing claim iat", err)
+	}
+
+	var malformedErr *MalformedError
+	if _, err := Decode("a.b"); !errors.As(err, &malformedErr) || err.Error() != "invalid token" {
+		t.Errorf("Decode() error = %v, want MalformedError", err)
+	}
+}
+
+func TestRegisterSigningMethod_None(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterSigningMethod() accepted algorithm none")
+		}
+	}()
+	RegisterSigningMethod(noneSigningMethod{})
+}
+
+func TestRegisterSigningMethod_Builtin(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterSigningMethod() replaced built-in algorithm")
+		}
+		if m, _ := GetSigningMethod("EdDSA"); m != SigningMethodEdDSA {
+			t.Error("GetSigningMethod() did not return built-in method")
+		}
+	}()
+	RegisterSigningMethod(eddsaSigningMethod{})
+}
+
+type eddsaSigningMethod struct {
+	testSigningMethod
+}
+
+func (eddsaSigningMethod) Alg() string {
+	return "EdDSA"
+}
+
+type noneSigningMethod struct {
+	testSigningMethod
+}
+
+func (noneSigningMethod) Alg() string {
+	return "none"
+}
@@ -0,0 +1,52 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// SigningMethodHS256 implements HMAC using SHA-256 as defined in RFC 7518
+// It only accepts keys of type HMACKey that are at least as long as the hash
+var SigningMethodHS256 SigningMethod = signingMethodHMAC{"HS256", sha256.New, minHMACKeySize}
+
+// minHMACKeySize is the size of the hash used by HS256 which is the minimum size of any HMACKey
+const minHMACKeySize = sha256.Size
+
+type signingMethodHMAC struct {
+	alg     string
+	hash    func() hash.Hash
+	minSize int
+}
+
+func (m signingMethodHMAC) Alg() string {
+	return m.alg
+}
+
+func (m signingMethodHMAC) mac(data []byte, key interface{}) ([]byte, error) {
+	k, ok := key.(HMACKey)
+	if !ok || len(k) < m.minSize {
+		return nil, errors.New("key is not a valid " + m.alg + " key")
+	}
+	h := hmac.New(m.hash, k)
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+func (m signingMethodHMAC) Sign(data []byte, key crypto.PrivateKey) ([]byte, error) {
+	return m.mac(data, key)
+}
+
+func (m signingMethodHMAC) Verify(data, signature []byte, key crypto.PublicKey) error {
+	mac, err := m.mac(data, key)
+	if err != nil {
+		return fmt.Errorf("%w for algorithm %s", ErrInvalidKey, m.alg)
+	}
+	if !hmac.Equal(mac, signature) {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// Package autherr describes verification errors to clients of the transport packages
+package autherr
+
+import (
+	"errors"
+
+	jwt "github.com/fossoreslp/go-jwt-ed25519"
+)
+
+// Describe returns a fixed description of err that is safe to send to clients
+// The descriptions only contain characters allowed by RFC 6750 Section 3 and don't reveal details like key IDs or key URLs
+func Describe(err error) string {
+	var claimErr *jwt.ClaimError
+	switch {
+	case errors.Is(err, jwt.ErrExpired):
+		return "the token has expired"
+	case errors.Is(err, jwt.ErrNotYetValid):
+		return "the token is not valid, yet"
+	case errors.Is(err, jwt.ErrMalformed):
+		return "the token is malformed"
+	case errors.Is(err, jwt.ErrAlgNotAllowed):
+		return "the token algorithm is not allowed"
+	case errors.Is(err, jwt.ErrReplayed):
+		return "the token has already been used"
+	case errors.Is(err, jwt.ErrSignatureInvalid), errors.Is(err, jwt.ErrUnknownKey):
+		return "the token signature is invalid"
+	case errors.As(err, &claimErr):
+		return claimErr.Error()
+	}
+	return "the token could not be verified"
+}
@@ -0,0 +1,250 @@
+package jwt
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// JSONJWS contains a payload and its signatures in JWS JSON serialization as defined in RFC 7515 Section 7.2
+// A payload may be signed by several keys, each signature having its own protected and unprotected header
+type JSONJWS struct {
+	Payload    []byte
+	Signatures []JSONSignature
+	payload    []byte // Encoded payload exactly as it appeared in the decoded JWS
+}
+
+// JSONSignature is a single signature of a JSONJWS
+// The algorithm is always part of the protected header while the key ID and key URL may be part of either header
+type JSONSignature struct {
+	Protected Header
+	Header    Header // Unprotected header
+	Signature []byte
+	protected []byte // Encoded protected header exactly as it appeared in the decoded JWS
+}
+
+// jsonSignature is a signature in JWS JSON serialization, in flattened serialization it is part of the JWS itself
+type jsonSignature struct {
+	Protected string  `json:"protected,omitempty"`
+	Header    *Header `json:"header,omitempty"`
+	Signature string  `json:"signature,omitempty"`
+}
+
+type jsonJWS struct {
+	Payload    string          `json:"payload"`
+	Signatures []jsonSignature `json:"signatures,omitempty"`
+	jsonSignature
+}
+
+// SignJSON returns payload signed by all of signers in general JWS JSON serialization
+// The protected header of each signature contains the algorithm, the unprotected header contains the key ID and key URL of the signer
+func SignJSON(payload []byte, signers ...*Signer) ([]byte, error) {
+	if len(signers) < 1 {
+		return nil, errors.New("at least one signer is required")
+	}
+	j := JSONJWS{Payload: payload}
+	for _, s := range signers {
+		err := j.Sign(s)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(j)
+}
+
+// DecodeJSON decodes a JWS in general or flattened JSON serialization without checking its signatures
+// Errors caused by the JWS itself match ErrMalformed
+func DecodeJSON(data []byte) (j JSONJWS, err error) {
+	err = j.UnmarshalJSON(data)
+	return
+}
+
+// Sign adds a signature of the payload by s, e.g. to co-sign a JWS signed by someone else
+func (j *JSONJWS) Sign(s *Signer) error {
+	protected := encodeHeader(Header{Alg: s.method.Alg()})
+	// The payload is already encoded and therefore inserted into the signing input as is
+	hash, err := s.method.Sign(signingInput(protected, j.encodedPayload(), false), s.key)
+	if err != nil {
+		return err
+	}
+	j.Signatures = append(j.Signatures, JSONSignature{
+		Protected: Header{Alg: s.method.Alg()},
+		Header:    Header{Kid: s.kid, Jku: s.jku},
+		Signature: hash,
+		protected: protected,
+	})
+	return nil
+}
+
+// VerifyAny returns nil if any of the signatures is valid for any of keys
+func (j *JSONJWS) VerifyAny(keys ...crypto.PublicKey) error {
+	err := errors.New("no keys available to validate jws")
+	for _, key := range keys {
+		err = j.verifyKey(key)
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// VerifyAll returns nil only if there is a valid signature for each of keys, e.g. to require a payload to be co-signed
+func (j *JSONJWS) VerifyAll(keys ...crypto.PublicKey) error {
+	if len(keys) < 1 {
+		return errors.New("no keys available to validate jws")
+	}
+	for _, key := range keys {
+		err := j.verifyKey(key)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyWithKeySet returns nil if any of the signatures is valid for the keys provided by ks for its header
+func (j *JSONJWS) VerifyWithKeySet(ks KeySet) error {
+	err := errors.New("jws does not contain any signatures")
+	for i := range j.Signatures {
+		s := j.jws(i)
+		err = s.VerifyWithKeySet(ks)
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// verifyKey returns nil if any of the signatures is valid for key
+func (j *JSONJWS) verifyKey(key crypto.PublicKey) error {
+	err := errors.New("jws does not contain any signatures")
+	for i := range j.Signatures {
+		s := j.jws(i)
+		err = s.Verify(key)
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// jws returns the i-th signature as JWS with the header containing both the protected and unprotected header
+func (j *JSONJWS) jws(i int) JWS {
+	s := j.Signatures[i]
+	h := s.Protected
+	if h.Kid == "" {
+		h.Kid = s.Header.Kid
+	}
+	if h.Jku == "" {
+		h.Jku = s.Header.Jku
+	}
+	protected := s.protected
+	if protected == nil {
+		protected = encodeHeader(s.Protected)
+	}
+	return JWS{Header: h, Payload: j.Payload, Signature: s.Signature, raw: signingInput(protected, j.encodedPayload(), false)}
+}
+
+func (j *JSONJWS) encodedPayload() []byte {
+	if j.payload != nil {
+		return j.payload
+	}
+	return b64encode(j.Payload)
+}
+
+// MarshalJSON encodes the JWS in general JSON serialization
+func (j JSONJWS) MarshalJSON() ([]byte, error) {
+	out := jsonJWS{Payload: string(j.encodedPayload()), Signatures: []jsonSignature{}}
+	for i := range j.Signatures {
+		out.Signatures = append(out.Signatures, j.Signatures[i].encode())
+	}
+	return json.Marshal(out)
+}
+
+// MarshalFlattened encodes the JWS in flattened JSON serialization which requires it to contain exactly one signature
+func (j JSONJWS) MarshalFlattened() ([]byte, error) {
+	if len(j.Signatures) != 1 {
+		return nil, errors.New("flattened serialization requires exactly one signature")
+	}
+	return json.Marshal(jsonJWS{Payload: string(j.encodedPayload()), jsonSignature: j.Signatures[0].encode()})
+}
+
+// UnmarshalJSON decodes a JWS in general or flattened JSON serialization
+func (j *JSONJWS) UnmarshalJSON(data []byte) error {
+	var in jsonJWS
+	err := json.Unmarshal(data, &in)
+	if err != nil {
+		return malformed(err)
+	}
+	if in.Signatures != nil && in.Signature != "" {
+		return malformed(errors.New("jws may not use general and flattened serialization at once"))
+	}
+	if in.Signatures == nil {
+		in.Signatures = []jsonSignature{in.jsonSignature}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(in.Payload)
+	if err != nil {
+		return malformed(err)
+	}
+	out := JSONJWS{Payload: payload, payload: []byte(in.Payload)}
+	for _, s := range in.Signatures {
+		sig, err := decodeJSONSignature(s)
+		if err != nil {
+			return err
+		}
+		out.Signatures = append(out.Signatures, sig)
+	}
+	*j = out
+	return nil
+}
+
+func (s JSONSignature) encode() jsonSignature {
+	protected := s.protected
+	if protected == nil {
+		protected = encodeHeader(s.Protected)
+	}
+	out := jsonSignature{Protected: string(protected), Signature: string(b64encode(s.Signature))}
+	if s.Header.Kid != "" || s.Header.Jku != "" {
+		h := Header{Kid: s.Header.Kid, Jku: s.Header.Jku}
+		out.Header = &h
+	}
+	return out
+}
+
+func decodeJSONSignature(in jsonSignature) (s JSONSignature, err error) {
+	protected, err := base64.RawURLEncoding.DecodeString(in.Protected)
+	if err != nil {
+		return s, malformed(err)
+	}
+	err = json.Unmarshal(protected, &s.Protected)
+	if err != nil {
+		return s, malformed(err)
+	}
+	encoded, err := checkCritical(s.Protected)
+	if err != nil {
+		return
+	}
+	if !encoded {
+		return s, malformed(errors.New("unencoded payloads are only supported for detached JWS"))
+	}
+	if in.Header != nil {
+		s.Header = *in.Header
+		// Only the key ID and key URL may be unprotected as all other parameters affect how the signature is checked
+		if s.Header.Typ != "" || s.Header.Alg != "" || s.Header.Cty != "" || s.Header.Crit != nil || s.Header.B64 != nil {
+			return s, malformed(errors.New("unprotected header may only contain kid and jku"))
+		}
+		if (s.Header.Kid != "" && s.Protected.Kid != "") || (s.Header.Jku != "" && s.Protected.Jku != "") {
+			return s, malformed(errors.New("protected and unprotected header may not contain the same parameters"))
+		}
+	}
+	s.Signature, err = base64.RawURLEncoding.DecodeString(in.Signature)
+	if err != nil {
+		return s, malformed(err)
+	}
+	if len(s.Signature) < 1 {
+		return s, malformed(errors.New("hash may not be empty"))
+	}
+	s.protected = []byte(in.Protected)
+	return s, nil
+}
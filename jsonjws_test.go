@@ -0,0 +1,170 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestJSONJWS_MarshalFlattened(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSignerWithKeyID(key, "e9bc097a-ce51-4036-9562-d2ade882db0d")
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	j := JSONJWS{Payload: []byte(`{"iss":"joe"}`)}
+	if err := j.Sign(s); err != nil {
+		t.Fatalf("Failed to sign payload: %s", err.Error())
+	}
+	enc, err := j.MarshalFlattened()
+	if err != nil {
+		t.Fatalf("Failed to encode JWS: %s", err.Error())
+	}
+	want := `{"payload":"eyJpc3MiOiJqb2UifQ","protected":"eyJhbGciOiJFUzI1NiJ9","header":{"kid":"e9bc097a-ce51-4036-9562-d2ade882db0d"},"signature":"` + base64.RawURLEncoding.EncodeToString(j.Signatures[0].Signature) + `"}`
+	if string(enc) != want {
+		t.Errorf("JSONJWS.MarshalFlattened() = %s, want %s", enc, want)
+	}
+
+	dec, err := DecodeJSON(enc)
+	if err != nil {
+		t.Fatalf("Failed to decode JWS: %s", err.Error())
+	}
+	if len(dec.Signatures) != 1 || dec.Signatures[0].Protected.Alg != "ES256" || dec.Signatures[0].Header.Kid != "e9bc097a-ce51-4036-9562-d2ade882db0d" {
+		t.Fatalf("DecodeJSON() = %+v", dec)
+	}
+	if err := dec.VerifyAny(&key.PublicKey); err != nil {
+		t.Errorf("Failed to verify JWS: %s", err.Error())
+	}
+	ks := NewMemoryKeySet()
+	if err := ks.Add("e9bc097a-ce51-4036-9562-d2ade882db0d", &key.PublicKey); err != nil {
+		t.Fatalf("Failed to add key: %s", err.Error())
+	}
+	if err := dec.VerifyWithKeySet(ks); err != nil {
+		t.Errorf("Failed to verify JWS with key set: %s", err.Error())
+	}
+	// Flattened and general serialization contain the same signature
+	general, err := json.Marshal(dec)
+	if err != nil {
+		t.Fatalf("Failed to encode JWS: %s", err.Error())
+	}
+	if _, err := DecodeJSON(general); err != nil {
+		t.Errorf("Failed to decode JWS in general serialization: %s", err.Error())
+	}
+}
+
+func TestSignJSON(t *testing.T) {
+	generate := func(kid string) (ed25519.PublicKey, *Signer) {
+		public, key, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+		}
+		s, err := NewSignerWithKeyID(key, kid)
+		if err != nil {
+			t.Fatalf("Failed to create signer: %s", err.Error())
+		}
+		return public, s
+	}
+	public1, s1 := generate("key1")
+	public2, s2 := generate("key2")
+	public3, _ := generate("key3")
+	payload := []byte("release manifest")
+
+	enc, err := SignJSON(payload, s1)
+	if err != nil {
+		t.Fatalf("Failed to sign payload: %s", err.Error())
+	}
+	j, err := DecodeJSON(enc)
+	if err != nil {
+		t.Fatalf("Failed to decode JWS: %s", err.Error())
+	}
+	if string(j.Payload) != string(payload) {
+		t.Errorf("DecodeJSON() payload = %s, want %s", j.Payload, payload)
+	}
+	if err := j.VerifyAll(public1, public2); err == nil {
+		t.Error("JSONJWS.VerifyAll() succeeded before payload was co-signed")
+	}
+
+	// Co-sign the decoded JWS and send it on
+	if err := j.Sign(s2); err != nil {
+		t.Fatalf("Failed to co-sign JWS: %s", err.Error())
+	}
+	enc, err = json.Marshal(j)
+	if err != nil {
+		t.Fatalf("Failed to encode JWS: %s", err.Error())
+	}
+	j, err = DecodeJSON(enc)
+	if err != nil {
+		t.Fatalf("Failed to decode JWS: %s", err.Error())
+	}
+	tests := []struct {
+		name    string
+		verify  func() error
+		wantErr bool
+	}{
+		{"All", func() error { return j.VerifyAll(public1, public2) }, false},
+		{"AllMissing", func() error { return j.VerifyAll(public1, public2, public3) }, true},
+		{"AllNoKeys", func() error { return j.VerifyAll() }, true},
+		{"Any", func() error { return j.VerifyAny(public3, public2) }, false},
+		{"AnyMissing", func() error { return j.VerifyAny(public3) }, true},
+		{"AnyNoKeys", func() error { return j.VerifyAny() }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.verify(); (err != nil) != tt.wantErr {
+				t.Errorf("Verify error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	ks := NewMemoryKeySet()
+	if err := ks.Add("key2", public2); err != nil {
+		t.Fatalf("Failed to add key: %s", err.Error())
+	}
+	if err := j.VerifyWithKeySet(ks); err != nil {
+		t.Errorf("Failed to verify JWS with key set: %s", err.Error())
+	}
+
+	// Modified payloads are not accepted
+	j.Payload = []byte("modified manifest")
+	j.payload = nil
+	if err := j.VerifyAny(public1, public2); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("JSONJWS.VerifyAny() error = %v, want %v", err, ErrSignatureInvalid)
+	}
+	if _, err := j.MarshalFlattened(); err == nil {
+		t.Error("JSONJWS.MarshalFlattened() accepted JWS with multiple signatures")
+	}
+	if _, err := SignJSON(payload); err == nil {
+		t.Error("SignJSON() accepted no signers")
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"InvalidJSON", `{`},
+		{"Payload", `{"payload":"!","protected":"eyJhbGciOiJFUzI1NiJ9","signature":"AA"}`},
+		{"MissingProtected", `{"payload":"AA","header":{"alg":"ES256"},"signature":"AA"}`},
+		{"EmptySignature", `{"payload":"AA","protected":"eyJhbGciOiJFUzI1NiJ9"}`},
+		{"UnprotectedAlgorithm", `{"payload":"AA","protected":"eyJhbGciOiJFUzI1NiJ9","header":{"alg":"HS256"},"signature":"AA"}`},
+		// Protected header {"alg":"ES256","kid":"a"}
+		{"DuplicateKeyID", `{"payload":"AA","protected":"eyJhbGciOiJFUzI1NiIsImtpZCI6ImEifQ","header":{"kid":"b"},"signature":"AA"}`},
+		{"GeneralAndFlattened", `{"payload":"AA","signatures":[{"protected":"eyJhbGciOiJFUzI1NiJ9","signature":"AA"}],"protected":"eyJhbGciOiJFUzI1NiJ9","signature":"AA"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeJSON([]byte(tt.in)); !errors.Is(err, ErrMalformed) {
+				t.Errorf("DecodeJSON() error = %v, want %v", err, ErrMalformed)
+			}
+		})
+	}
+}
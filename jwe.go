@@ -0,0 +1,236 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/ed25519"
+)
+
+// Size of the content encryption key in bytes used by all supported content encryption algorithms
+const cekSize = 32
+
+// Encrypter encrypts payloads for a single recipient as JWE in compact serialization as defined in RFC 7516
+// The key is agreed upon using ECDH-ES with X25519 as defined in RFC 8037 and either used directly (ECDH-ES) or to wrap a random key (ECDH-ES+A256KW)
+// Content is encrypted using A256GCM or C20P (ChaCha20-Poly1305)
+// An Encrypter is immutable and may therefore be used by multiple goroutines at once
+type Encrypter struct {
+	key *ecdh.PublicKey
+	alg string
+	enc string
+	kid string
+}
+
+// NewEncrypter returns a new Encrypter for the recipient key using the key management algorithm alg and the content encryption algorithm enc
+// The key may either be an X25519 *ecdh.PublicKey or an ed25519.PublicKey which is converted to X25519
+func NewEncrypter(key crypto.PublicKey, alg, enc string) (*Encrypter, error) {
+	var k *ecdh.PublicKey
+	switch key := edwardsPublicKey(key).(type) {
+	case *ecdh.PublicKey:
+		if key == nil || key.Curve() != ecdh.X25519() {
+			return nil, ErrInvalidKey
+		}
+		k = key
+	case ed25519.PublicKey:
+		var err error
+		k, err = X25519PublicKey(key)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrInvalidKey
+	}
+	if alg != "ECDH-ES" && alg != "ECDH-ES+A256KW" {
+		return nil, &AlgorithmError{Alg: alg, Unsupported: true}
+	}
+	if enc != "A256GCM" && enc != "C20P" {
+		return nil, &AlgorithmError{Alg: enc, Unsupported: true}
+	}
+	return &Encrypter{key: k, alg: alg, enc: enc}, nil
+}
+
+// NewEncrypterWithKeyID returns a new Encrypter that inserts the key ID of the recipient key into the header
+func NewEncrypterWithKeyID(key crypto.PublicKey, alg, enc, keyID string) (*Encrypter, error) {
+	if keyID == "" {
+		return nil, errors.New("empty key IDs are not supported")
+	}
+	e, err := NewEncrypter(key, alg, enc)
+	if err != nil {
+		return nil, err
+	}
+	e.kid = keyID
+	return e, nil
+}
+
+// Encrypt returns plaintext encrypted for the recipient as JWE in compact serialization
+// Typ and cty are inserted into the header as type and content type and are omitted if empty
+func (e *Encrypter) Encrypt(plaintext []byte, typ, cty string) ([]byte, error) {
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	epk, err := NewJWK(ephemeral.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	epk.Use = ""
+	z, err := ephemeral.ECDH(e.key)
+	if err != nil {
+		return nil, err
+	}
+
+	h := Header{Typ: typ, Cty: cty, Alg: e.alg, Enc: e.enc, Kid: e.kid, Epk: &epk}
+	var cek, encryptedKey []byte
+	if e.alg == "ECDH-ES" {
+		cek = concatKDF(z, e.enc, nil, nil, cekSize)
+	} else {
+		cek = make([]byte, cekSize)
+		_, err = rand.Read(cek)
+		if err != nil {
+			return nil, err
+		}
+		encryptedKey, err = wrapKey(concatKDF(z, e.alg, nil, nil, cekSize), cek)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	aead, err := newAEAD(e.enc, cek)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, aead.NonceSize())
+	_, err = rand.Read(iv)
+	if err != nil {
+		return nil, err
+	}
+	header := encodeHeader(h)
+	// The encoded protected header is used as additional authenticated data
+	sealed := aead.Seal(nil, iv, plaintext, header)
+	ciphertext, tag := sealed[:len(plaintext)], sealed[len(plaintext):]
+	return join(header, b64encode(encryptedKey), b64encode(iv), b64encode(ciphertext), b64encode(tag)), nil
+}
+
+// Decrypt decrypts a JWE in compact serialization using key and returns its header and plaintext
+// The key may either be an X25519 *ecdh.PrivateKey or an ed25519.PrivateKey which is converted to X25519
+// Only the algorithms supported by Encrypter are accepted and all errors caused by the content or keys match ErrDecryption
+func Decrypt(token string, key crypto.PrivateKey) (Header, []byte, error) {
+	var k *ecdh.PrivateKey
+	switch key := edwardsPrivateKey(key).(type) {
+	case *ecdh.PrivateKey:
+		if key == nil || key.Curve() != ecdh.X25519() {
+			return Header{}, nil, errors.New("key is not a valid private key")
+		}
+		k = key
+	case ed25519.PrivateKey:
+		var err error
+		k, err = X25519PrivateKey(key)
+		if err != nil {
+			return Header{}, nil, err
+		}
+	default:
+		return Header{}, nil, errors.New("key is not a valid private key")
+	}
+
+	// Split the JWE into it's sections (header, encrypted key, initialization vector, ciphertext, authentication tag)
+	sections := strings.Split(token, ".")
+	if len(sections) != 5 {
+		return Header{}, nil, malformed(errors.New("invalid token"))
+	}
+	decoded := make([][]byte, len(sections))
+	for i, s := range sections {
+		var err error
+		decoded[i], err = base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			return Header{}, nil, malformed(err)
+		}
+	}
+	var h Header
+	err := json.Unmarshal(decoded[0], &h)
+	if err != nil {
+		return Header{}, nil, malformed(err)
+	}
+	if len(h.Crit) > 0 {
+		return Header{}, nil, malformed(errors.New("critical header parameters are not supported for JWE"))
+	}
+	// The plaintext of a compressed JWE would otherwise be returned without being decompressed
+	if h.Zip != "" {
+		return Header{}, nil, &AlgorithmError{Alg: h.Zip, Unsupported: true}
+	}
+	if h.Alg != "ECDH-ES" && h.Alg != "ECDH-ES+A256KW" {
+		return Header{}, nil, &AlgorithmError{Alg: h.Alg, Unsupported: true}
+	}
+	if h.Enc != "A256GCM" && h.Enc != "C20P" {
+		return Header{}, nil, &AlgorithmError{Alg: h.Enc, Unsupported: true}
+	}
+	if h.Epk == nil || h.Epk.Crv != "X25519" || h.Epk.D != "" {
+		return Header{}, nil, malformed(errors.New("header does not contain an ephemeral X25519 public key"))
+	}
+	epk, err := h.Epk.PublicKey()
+	if err != nil {
+		return Header{}, nil, malformed(err)
+	}
+	apu, err := base64.RawURLEncoding.DecodeString(h.Apu)
+	if err != nil {
+		return Header{}, nil, malformed(err)
+	}
+	apv, err := base64.RawURLEncoding.DecodeString(h.Apv)
+	if err != nil {
+		return Header{}, nil, malformed(err)
+	}
+
+	// Low order points result in an all zero shared secret which is rejected
+	z, err := k.ECDH(epk.(*ecdh.PublicKey))
+	if err != nil {
+		return Header{}, nil, ErrDecryption
+	}
+	var cek []byte
+	if h.Alg == "ECDH-ES" {
+		if len(decoded[1]) != 0 {
+			return Header{}, nil, malformed(errors.New("encrypted key has to be empty for direct key agreement"))
+		}
+		cek = concatKDF(z, h.Enc, apu, apv, cekSize)
+	} else {
+		cek, err = unwrapKey(concatKDF(z, h.Alg, apu, apv, cekSize), decoded[1])
+		if err != nil || len(cek) != cekSize {
+			return Header{}, nil, ErrDecryption
+		}
+	}
+
+	aead, err := newAEAD(h.Enc, cek)
+	if err != nil {
+		return Header{}, nil, err
+	}
+	iv, ciphertext, tag := decoded[2], decoded[3], decoded[4]
+	if len(iv) != aead.NonceSize() || len(tag) != aead.Overhead() {
+		return Header{}, nil, malformed(errors.New("invalid initialization vector or authentication tag"))
+	}
+	plaintext, err := aead.Open(nil, iv, append(ciphertext, tag...), []byte(sections[0]))
+	if err != nil {
+		return Header{}, nil, ErrDecryption
+	}
+	return h, plaintext, nil
+}
+
+// newAEAD returns the content encryption algorithm enc using cek
+func newAEAD(enc string, cek []byte) (cipher.AEAD, error) {
+	switch enc {
+	case "A256GCM":
+		block, err := aes.NewCipher(cek)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case "C20P":
+		return chacha20poly1305.New(cek)
+	}
+	return nil, &AlgorithmError{Alg: enc, Unsupported: true}
+}
@@ -0,0 +1,226 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestEncrypter_Encrypt(t *testing.T) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	wrongKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	edPublic, edKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	plaintext := []byte(`{"email":"user@example.com"}`)
+	tests := []struct {
+		name    string
+		public  crypto.PublicKey
+		private crypto.PrivateKey
+		alg     string
+		enc     string
+	}{
+		{"DirectA256GCM", key.PublicKey(), key, "ECDH-ES", "A256GCM"},
+		{"DirectC20P", key.PublicKey(), key, "ECDH-ES", "C20P"},
+		{"KeyWrapA256GCM", key.PublicKey(), key, "ECDH-ES+A256KW", "A256GCM"},
+		{"KeyWrapC20P", key.PublicKey(), key, "ECDH-ES+A256KW", "C20P"},
+		{"Ed25519", edPublic, edKey, "ECDH-ES+A256KW", "A256GCM"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := NewEncrypterWithKeyID(tt.public, tt.alg, tt.enc, "recipient")
+			if err != nil {
+				t.Fatalf("Failed to create encrypter: %s", err.Error())
+			}
+			enc, err := e.Encrypt(plaintext, "JWT", "")
+			if err != nil {
+				t.Fatalf("Failed to encrypt payload: %s", err.Error())
+			}
+			if bytes.Contains(enc, []byte("user@example.com")) || bytes.Contains(enc, b64encode(plaintext)) {
+				t.Fatal("Encrypted token contains plaintext")
+			}
+			h, got, err := Decrypt(string(enc), tt.private)
+			if err != nil {
+				t.Fatalf("Failed to decrypt token: %s", err.Error())
+			}
+			if !bytes.Equal(got, plaintext) {
+				t.Errorf("Decrypt() = %s, want %s", got, plaintext)
+			}
+			if h.Alg != tt.alg || h.Enc != tt.enc || h.Kid != "recipient" || h.Typ != "JWT" || h.Epk == nil || h.Epk.Crv != "X25519" {
+				t.Errorf("Decrypt() header = %+v", h)
+			}
+			if _, _, err := Decrypt(string(enc), wrongKey); !errors.Is(err, ErrDecryption) {
+				t.Errorf("Decrypt() error = %v using wrong key, want %v", err, ErrDecryption)
+			}
+
+			// Modifying any section makes decryption fail
+			sections := strings.Split(string(enc), ".")
+			for i := range sections {
+				modified := append([]string(nil), sections...)
+				data := []byte(modified[i])
+				if len(data) == 0 {
+					continue
+				}
+				data[0] ^= 'A' ^ 'B'
+				modified[i] = string(data)
+				if _, _, err := Decrypt(strings.Join(modified, "."), tt.private); err == nil {
+					t.Errorf("Decrypt() accepted token with modified section %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestNewEncrypter(t *testing.T) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	p256, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	tests := []struct {
+		name string
+		key  crypto.PublicKey
+		alg  string
+		enc  string
+	}{
+		{"P256", p256.PublicKey(), "ECDH-ES", "A256GCM"},
+		{"InvalidEd25519", ed25519.PublicKey("test"), "ECDH-ES", "A256GCM"},
+		{"HMACKey", HMACKey("a shared secret that is long enough for HS256"), "ECDH-ES", "A256GCM"},
+		{"UnsupportedAlgorithm", key.PublicKey(), "RSA-OAEP", "A256GCM"},
+		{"UnsupportedEncryption", key.PublicKey(), "ECDH-ES", "A128CBC-HS256"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewEncrypter(tt.key, tt.alg, tt.enc); err == nil {
+				t.Error("NewEncrypter() accepted invalid parameters")
+			}
+		})
+	}
+	if _, err := NewEncrypterWithKeyID(key.PublicKey(), "ECDH-ES", "A256GCM", ""); err == nil {
+		t.Error("NewEncrypterWithKeyID() accepted empty key ID")
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"Sections", "eyJhbGciOiJFQ0RILUVTIn0..AA.AA"},
+		// Header {"alg":"dir","enc":"A256GCM"}
+		{"UnsupportedAlgorithm", "eyJhbGciOiJkaXIiLCJlbmMiOiJBMjU2R0NNIn0..AA.AA.AA"},
+		// Header {"alg":"ECDH-ES","enc":"A128GCM"}
+		{"UnsupportedEncryption", "eyJhbGciOiJFQ0RILUVTIiwiZW5jIjoiQTEyOEdDTSJ9..AA.AA.AA"},
+		// Header {"alg":"ECDH-ES","enc":"A256GCM"}
+		{"MissingEphemeralKey", "eyJhbGciOiJFQ0RILUVTIiwiZW5jIjoiQTI1NkdDTSJ9..AA.AA.AA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Decrypt(tt.token, key); err == nil {
+				t.Error("Decrypt() accepted invalid token")
+			}
+		})
+	}
+
+	// Compressed tokens are rejected before decryption, header {"alg":"ECDH-ES","enc":"A256GCM","zip":"DEF"}
+	var algErr *AlgorithmError
+	if _, _, err := Decrypt("eyJhbGciOiJFQ0RILUVTIiwiZW5jIjoiQTI1NkdDTSIsInppcCI6IkRFRiJ9..AA.AA.AA", key); !errors.As(err, &algErr) || algErr.Alg != "DEF" {
+		t.Errorf("Decrypt() error = %v, want unsupported compression algorithm DEF", err)
+	}
+	if _, _, err := Decrypt("", HMACKey("a shared secret that is long enough for HS256")); err == nil {
+		t.Error("Decrypt() accepted invalid key")
+	}
+}
+
+func TestX25519(t *testing.T) {
+	// Ed25519 keys converted to X25519 have to result in a matching key pair
+	for i := 0; i < 16; i++ {
+		public, key, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+		}
+		xKey, err := X25519PrivateKey(key)
+		if err != nil {
+			t.Fatalf("Failed to convert private key: %s", err.Error())
+		}
+		xPublic, err := X25519PublicKey(public)
+		if err != nil {
+			t.Fatalf("Failed to convert public key: %s", err.Error())
+		}
+		if !xKey.PublicKey().Equal(xPublic) {
+			t.Fatalf("X25519PublicKey() = %x, want %x", xPublic.Bytes(), xKey.PublicKey().Bytes())
+		}
+	}
+	if _, err := X25519PublicKey(ed25519.PublicKey("test")); err == nil {
+		t.Error("X25519PublicKey() accepted invalid key")
+	}
+	// y = 1 is the neutral element which has no corresponding Montgomery point
+	one := make(ed25519.PublicKey, ed25519.PublicKeySize)
+	one[0] = 1
+	if _, err := X25519PublicKey(one); err == nil {
+		t.Error("X25519PublicKey() accepted neutral element")
+	}
+
+	// X25519 keys can be represented as JWK
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	k, err := NewPrivateJWK(key)
+	if err != nil {
+		t.Fatalf("Failed to create JWK: %s", err.Error())
+	}
+	if k.Crv != "X25519" || k.Use != "enc" || k.Alg != "" {
+		t.Errorf("NewPrivateJWK() = %+v", k)
+	}
+	parsed, err := k.PrivateKey()
+	if err != nil || !key.Equal(parsed) {
+		t.Errorf("JWK.PrivateKey() = %v, %v, want original key", parsed, err)
+	}
+}
+
+func Test_wrapKey(t *testing.T) {
+	// Test vector taken from RFC 3394 Section 4.6
+	kek, _ := hex.DecodeString("000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F")
+	key, _ := hex.DecodeString("00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F")
+	want, _ := hex.DecodeString("28C9F404C4B810F4CBCCB35CFB87F8263F5786E2D80ED326CBC7F0E71A99F43BFB988B9B7A02DD21")
+	got, err := wrapKey(kek, key)
+	if err != nil {
+		t.Fatalf("wrapKey() error = %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("wrapKey() = %X, want %X", got, want)
+	}
+	unwrapped, err := unwrapKey(kek, got)
+	if err != nil {
+		t.Fatalf("unwrapKey() error = %v", err)
+	}
+	if !bytes.Equal(unwrapped, key) {
+		t.Errorf("unwrapKey() = %X, want %X", unwrapped, key)
+	}
+	got[0] ^= 1
+	if _, err := unwrapKey(kek, got); err == nil {
+		t.Error("unwrapKey() accepted modified key")
+	}
+}
@@ -0,0 +1,218 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/cloudflare/circl/sign/ed448"
+	"golang.org/x/crypto/ed25519"
+)
+
+// JWK is a JSON web key as defined in RFC 7517 containing an Ed25519, Ed448 or X25519 octet key pair as defined in RFC 8037
+// Private keys contain D while public keys only contain X
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	D   string `json:"d,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+}
+
+// JWKS is a JSON web key set as defined in RFC 7517
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWK returns a JWK containing the public key which may either be an ed25519.PublicKey or an ed448.PublicKey
+// X25519 keys used for encryption may be supplied as *ecdh.PublicKey
+func NewJWK(key crypto.PublicKey) (JWK, error) {
+	if key, ok := key.(*ecdh.PublicKey); ok {
+		if key == nil || key.Curve() != ecdh.X25519() {
+			return JWK{}, ErrInvalidKey
+		}
+		return JWK{Kty: "OKP", Crv: "X25519", X: string(b64encode(key.Bytes())), Use: "enc"}, nil
+	}
+	err := checkPublicKey(key)
+	if err != nil {
+		return JWK{}, err
+	}
+	k := JWK{Kty: "OKP", Use: "sig", Alg: "EdDSA"}
+	switch key := edwardsPublicKey(key).(type) {
+	case ed25519.PublicKey:
+		k.Crv = "Ed25519"
+		k.X = string(b64encode(key))
+	case ed448.PublicKey:
+		k.Crv = "Ed448"
+		k.X = string(b64encode(key))
+	}
+	return k, nil
+}
+
+// NewPrivateJWK returns a JWK containing the private key which may either be an ed25519.PrivateKey or an ed448.PrivateKey
+// X25519 keys used for encryption may be supplied as *ecdh.PrivateKey
+func NewPrivateJWK(key crypto.PrivateKey) (JWK, error) {
+	if key, ok := key.(*ecdh.PrivateKey); ok {
+		if key == nil {
+			return JWK{}, errors.New("key is not a valid private key")
+		}
+		k, err := NewJWK(key.PublicKey())
+		if err != nil {
+			return JWK{}, err
+		}
+		k.D = string(b64encode(key.Bytes()))
+		return k, nil
+	}
+	err := checkPrivateKey(key)
+	if err != nil {
+		return JWK{}, err
+	}
+	k, _ := NewJWK(publicKeyFor(key)) // Error is safe to ignore as the public key of a valid private key is always valid
+	switch key := edwardsPrivateKey(key).(type) {
+	case ed25519.PrivateKey:
+		k.D = string(b64encode(key.Seed()))
+	case ed448.PrivateKey:
+		k.D = string(b64encode(key.Seed()))
+	}
+	return k, nil
+}
+
+// PublicKey returns the public key contained in k as ed25519.PublicKey, ed448.PublicKey or *ecdh.PublicKey for X25519 depending on the curve
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	err := k.check()
+	if err != nil {
+		return nil, err
+	}
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, err
+	}
+	if k.Crv == "X25519" {
+		key, err := ecdh.X25519().NewPublicKey(x)
+		if err != nil {
+			return nil, ErrInvalidKey
+		}
+		return key, nil
+	}
+	var key crypto.PublicKey = ed25519.PublicKey(x)
+	if k.Crv == "Ed448" {
+		key = ed448.PublicKey(x)
+	}
+	err = checkPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// PrivateKey returns the private key contained in k as ed25519.PrivateKey, ed448.PrivateKey or *ecdh.PrivateKey for X25519 depending on the curve
+// An error is returned if k only contains a public key or the private key does not match the public key
+func (k JWK) PrivateKey() (crypto.PrivateKey, error) {
+	public, err := k.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	if k.D == "" {
+		return nil, errors.New("key does not contain a private key")
+	}
+	d, err := base64.RawURLEncoding.DecodeString(k.D)
+	if err != nil {
+		return nil, err
+	}
+	var private interface{ Public() crypto.PublicKey }
+	switch {
+	case k.Crv == "Ed25519" && len(d) == ed25519.SeedSize:
+		private = ed25519.NewKeyFromSeed(d)
+	case k.Crv == "Ed448" && len(d) == ed448.SeedSize:
+		private = ed448.NewKeyFromSeed(d)
+	case k.Crv == "X25519" && len(d) == 32:
+		private, _ = ecdh.X25519().NewPrivateKey(d) // Error is safe to ignore as any 32 bytes are a valid X25519 private key
+	default:
+		return nil, errors.New("key is not a valid private key")
+	}
+	if !private.Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(public) {
+		return nil, errors.New("private key does not match public key")
+	}
+	return private, nil
+}
+
+// Public returns a copy of k without the private key
+func (k JWK) Public() JWK {
+	k.D = ""
+	return k
+}
+
+// Thumbprint returns the JWK thumbprint as defined in RFC 7638 using SHA-256 encoded as base64url
+// It can be used as key ID as it only depends on the public key
+func (k JWK) Thumbprint() (string, error) {
+	err := k.check()
+	if err != nil {
+		return "", err
+	}
+	// The required members have to be in lexicographic order without any whitespace, which encoding/json guarantees for maps
+	data, err := json.Marshal(map[string]string{"crv": k.Crv, "kty": k.Kty, "x": k.X})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return string(b64encode(sum[:])), nil
+}
+
+func (k JWK) check() error {
+	if k.Kty != "OKP" {
+		return errors.New("key type " + k.Kty + " not supported")
+	}
+	if k.Crv != "Ed25519" && k.Crv != "Ed448" && k.Crv != "X25519" {
+		return errors.New("curve " + k.Crv + " not supported")
+	}
+	return nil
+}
+
+// Key returns the key with key ID kid
+func (s JWKS) Key(kid string) (JWK, bool) {
+	for _, k := range s.Keys {
+		if k.Kid == kid {
+			return k, true
+		}
+	}
+	return JWK{}, false
+}
+
+// JWKSHandler returns a http.Handler serving the public keys of signers as JWKS
+// The key ID of each signer is used as key ID of its key and signers not using EdDSA are skipped
+// An error is returned if any of the signers is nil
+func JWKSHandler(signers ...*Signer) (http.Handler, error) {
+	set := JWKS{Keys: make([]JWK, 0, len(signers))}
+	for _, s := range signers {
+		if s == nil {
+			return nil, errors.New("signer may not be nil")
+		}
+		k, err := NewJWK(s.Public())
+		if err != nil {
+			// Only Ed25519 and Ed448 keys can be represented as JWK which also ensures shared secrets are never published
+			continue
+		}
+		k.Kid = s.KeyID()
+		set.Keys = append(set.Keys, k)
+	}
+	data, _ := json.Marshal(set) // Error is safe to ignore as encoding a struct containing only strings can't fail
+	return jwksHandler(data), nil
+}
+
+type jwksHandler []byte
+
+func (h jwksHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+	w.Write(h)
+}
@@ -0,0 +1,182 @@
+package jwt
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+// Test vectors taken from RFC 8037 Appendix A
+const (
+	rfc8037D          = "nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A"
+	rfc8037X          = "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"
+	rfc8037Thumbprint = "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"
+)
+
+func TestJWK_PrivateKey(t *testing.T) {
+	k := JWK{Kty: "OKP", Crv: "Ed25519", X: rfc8037X, D: rfc8037D}
+	private, err := k.PrivateKey()
+	if err != nil {
+		t.Fatalf("Failed to get private key from JWK: %s", err.Error())
+	}
+	enc, err := NewPrivateJWK(private)
+	if err != nil {
+		t.Fatalf("Failed to create JWK from private key: %s", err.Error())
+	}
+	if enc.X != rfc8037X || enc.D != rfc8037D {
+		t.Errorf("NewPrivateJWK() = %+v, want x = %s and d = %s", enc, rfc8037X, rfc8037D)
+	}
+	if enc.Public().D != "" {
+		t.Error("JWK.Public() did not remove private key")
+	}
+
+	tests := []struct {
+		name string
+		key  JWK
+	}{
+		{"PublicOnly", JWK{Kty: "OKP", Crv: "Ed25519", X: rfc8037X}},
+		{"Mismatch", JWK{Kty: "OKP", Crv: "Ed25519", X: rfc8037X, D: "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"}},
+		{"InvalidBase64", JWK{Kty: "OKP", Crv: "Ed25519", X: rfc8037X, D: "A"}},
+		{"TooShort", JWK{Kty: "OKP", Crv: "Ed25519", X: rfc8037X, D: "AAAA"}},
+		{"InvalidPublicKey", JWK{Kty: "OKP", Crv: "Ed25519", X: "AAAA", D: rfc8037D}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.key.PrivateKey(); err == nil {
+				t.Error("JWK.PrivateKey() accepted invalid key")
+			}
+		})
+	}
+}
+
+func TestJWK_PublicKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     JWK
+		wantErr bool
+	}{
+		{"Normal", JWK{Kty: "OKP", Crv: "Ed25519", X: rfc8037X}, false},
+		{"WrongKeyType", JWK{Kty: "EC", Crv: "Ed25519", X: rfc8037X}, true},
+		{"WrongCurve", JWK{Kty: "OKP", Crv: "X448", X: rfc8037X}, true},
+		{"InvalidBase64", JWK{Kty: "OKP", Crv: "Ed25519", X: "A"}, true},
+		{"TooShort", JWK{Kty: "OKP", Crv: "Ed25519", X: "AAAA"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.key.PublicKey()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JWK.PublicKey() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			k, err := NewJWK(got)
+			if err != nil {
+				t.Fatalf("Failed to create JWK from public key: %s", err.Error())
+			}
+			if k.X != tt.key.X {
+				t.Errorf("NewJWK() x = %s, want %s", k.X, tt.key.X)
+			}
+		})
+	}
+	if _, err := NewJWK(ed25519.PublicKey("test")); err == nil {
+		t.Error("NewJWK() accepted invalid public key")
+	}
+	if _, err := NewPrivateJWK(ed25519.PrivateKey("test")); err == nil {
+		t.Error("NewPrivateJWK() accepted invalid private key")
+	}
+}
+
+func TestJWK_Thumbprint(t *testing.T) {
+	k := JWK{Kty: "OKP", Crv: "Ed25519", X: rfc8037X, D: rfc8037D, Kid: "ignored", Use: "sig"}
+	got, err := k.Thumbprint()
+	if err != nil {
+		t.Fatalf("Failed to compute thumbprint: %s", err.Error())
+	}
+	if got != rfc8037Thumbprint {
+		t.Errorf("JWK.Thumbprint() = %s, want %s", got, rfc8037Thumbprint)
+	}
+	if _, err := (JWK{Kty: "RSA"}).Thumbprint(); err == nil {
+		t.Error("JWK.Thumbprint() accepted unsupported key type")
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	in := []byte(`{"keys":[{"kty":"OKP","crv":"Ed25519","x":"` + rfc8037X + `","kid":"key1"},{"kty":"OKP","crv":"Ed25519","x":"` + rfc8037X + `","kid":"key2","use":"sig"}]}`)
+	var set JWKS
+	if err := json.Unmarshal(in, &set); err != nil {
+		t.Fatalf("Failed to unmarshal JWKS: %s", err.Error())
+	}
+	k, ok := set.Key("key2")
+	if !ok || k.Use != "sig" {
+		t.Errorf("JWKS.Key() = %+v, %v, want key2", k, ok)
+	}
+	if _, ok := set.Key("key3"); ok {
+		t.Error("JWKS.Key() found key that does not exist")
+	}
+	out, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("Failed to marshal JWKS: %s", err.Error())
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("JWKS marshaled to %s, want %s", out, in)
+	}
+}
+
+func TestJWKSHandler(t *testing.T) {
+	public1, key1, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	_, key2, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s1, err := NewSignerWithKeyID(key1, "key1")
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	s2, err := NewSignerWithKeyID(key2, "key2")
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	h, err := JWKSHandler(s1, s2)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %s", err.Error())
+	}
+	if _, err := JWKSHandler(s1, nil); err == nil {
+		t.Error("JWKSHandler() accepted nil signer")
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/jwk-set+json" {
+		t.Fatalf("JWKSHandler() responded with status %d and content type %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	var set JWKS
+	if err := json.Unmarshal(rec.Body.Bytes(), &set); err != nil {
+		t.Fatalf("Failed to unmarshal JWKS: %s", err.Error())
+	}
+	if len(set.Keys) != 2 {
+		t.Fatalf("JWKSHandler() served %d keys, want 2", len(set.Keys))
+	}
+	k, ok := set.Key("key1")
+	if !ok || k.D != "" {
+		t.Fatalf("JWKSHandler() served %+v for key1", k)
+	}
+	public, err := k.PublicKey()
+	if err != nil || !public1.Equal(public) {
+		t.Errorf("JWKSHandler() served wrong public key for key1")
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/.well-known/jwks.json", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("JWKSHandler() responded to POST with status %d", rec.Code)
+	}
+}
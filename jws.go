@@ -0,0 +1,144 @@
+package jwt
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// JWS contains the header and payload of a JSON web signature in compact serialization as well as the decoded signature
+// Unlike a JWT, the payload may contain arbitrary data and the header may use any type
+type JWS struct {
+	Header    Header
+	Payload   []byte
+	Signature []byte
+	raw       []byte // Encoded header and payload exactly as they appeared in the decoded token
+}
+
+// SignJWS returns payload signed by s as JWS in compact serialization
+// Typ and cty are inserted into the header as type and content type and are omitted if empty
+func (s *Signer) SignJWS(payload []byte, typ, cty string) ([]byte, error) {
+	return s.encodeJWS(Header{Typ: typ, Cty: cty, Kid: s.kid, Jku: s.jku}, payload)
+}
+
+// encodeJWS signs payload using h as header with the algorithm set to the one used by s
+func (s *Signer) encodeJWS(h Header, payload []byte) ([]byte, error) {
+	h.Alg = s.method.Alg()
+	data := signingInput(encodeHeader(h), payload, true)
+	hash, err := s.method.Sign(data, s.key)
+	if err != nil {
+		return nil, err
+	}
+	return join(data, b64encode(hash)), nil
+}
+
+// DecodeJWS decodes a JWS in compact serialization without checking its signature
+// Errors caused by the token itself match ErrMalformed
+func DecodeJWS(token string) (data JWS, err error) {
+	// Split the JWS into it's sections (header, payload, signature)
+	sections := strings.Split(token, ".")
+	if len(sections) != 3 {
+		err = malformed(errors.New("invalid token"))
+		return
+	}
+
+	// Decode first section to header
+	headerData, err := base64.RawURLEncoding.DecodeString(sections[0])
+	if err != nil {
+		err = malformed(err)
+		return
+	}
+	err = json.Unmarshal(headerData, &data.Header)
+	if err != nil {
+		err = malformed(err)
+		return
+	}
+	encoded, err := checkCritical(data.Header)
+	if err != nil {
+		return
+	}
+	if !encoded {
+		err = malformed(errors.New("unencoded payloads are only supported for detached JWS"))
+		return
+	}
+
+	// Decode second section to payload
+	data.Payload, err = base64.RawURLEncoding.DecodeString(sections[1])
+	if err != nil {
+		err = malformed(err)
+		return
+	}
+
+	// Decode third section to signature
+	data.Signature, err = base64.RawURLEncoding.DecodeString(sections[2])
+	if err != nil {
+		err = malformed(err)
+		return
+	}
+	if len(data.Signature) < 1 {
+		err = malformed(errors.New("hash may not be empty"))
+		return
+	}
+
+	// Keep the encoded header and payload as the signature has to be verified against them
+	data.raw = []byte(token[:len(sections[0])+1+len(sections[1])])
+	return
+}
+
+// VerifyJWS decodes token and returns it only if its signature is valid for key
+func VerifyJWS(token string, key crypto.PublicKey) (JWS, error) {
+	j, err := DecodeJWS(token)
+	if err != nil {
+		return JWS{}, err
+	}
+	err = j.Verify(key)
+	if err != nil {
+		return JWS{}, err
+	}
+	return j, nil
+}
+
+// Verify returns an error if the signature does not match the header and payload for key
+// The signing method is chosen using the alg header and has to accept the type of key
+// For decoded signatures the header and payload are checked as they appeared in the token, so changes made after decoding are not taken into account
+func (j *JWS) Verify(key crypto.PublicKey) error {
+	err := checkPublicKey(key)
+	if err != nil {
+		return err
+	}
+	method, err := methodForAlg(j.Header.Alg)
+	if err != nil {
+		return err
+	}
+	data := j.raw
+	if data == nil {
+		data = signingInput(encodeHeader(j.Header), j.Payload, true)
+	}
+	return method.Verify(data, j.Signature, key)
+}
+
+// VerifyWithKeySet behaves like Verify but uses the keys provided by ks for the header
+// The signature is valid if it matches any of these keys
+func (j *JWS) VerifyWithKeySet(ks KeySet) error {
+	return verifyWithKeySet(ks, j.Header, j.Verify)
+}
+
+// verifyWithKeySet succeeds if verify succeeds for any of the keys provided by ks for h
+func verifyWithKeySet(ks KeySet, h Header, verify func(crypto.PublicKey) error) error {
+	keys, err := ks.Keys(h)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		err = verify(key)
+		if err == nil {
+			return nil
+		}
+	}
+	if err == nil {
+		err = errors.New("no keys available to validate token")
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+package jwt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestSigner_SignJWS(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	wrongKey, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSignerWithKeyID(key, "manifest")
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	tests := []struct {
+		name    string
+		payload []byte
+		typ     string
+		cty     string
+	}{
+		{"Binary", []byte{0x00, 0xff, 0x2e, 0x80}, "", ""},
+		{"CBOR", []byte{0xa1, 0x63, 's', 'u', 'b', 0x64, 't', 'e', 's', 't'}, "", "application/cbor"},
+		{"Typed", []byte("manifest"), "manifest+jws", "text/plain"},
+		{"Empty", []byte{}, "JOSE", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc, err := s.SignJWS(tt.payload, tt.typ, tt.cty)
+			if err != nil {
+				t.Fatalf("Failed to sign payload: %s", err.Error())
+			}
+			j, err := VerifyJWS(string(enc), public)
+			if err != nil {
+				t.Fatalf("Failed to verify JWS: %s", err.Error())
+			}
+			if !bytes.Equal(j.Payload, tt.payload) {
+				t.Errorf("VerifyJWS() payload = %x, want %x", j.Payload, tt.payload)
+			}
+			if j.Header.Typ != tt.typ || j.Header.Cty != tt.cty || j.Header.Kid != "manifest" || j.Header.Alg != "EdDSA" {
+				t.Errorf("VerifyJWS() header = %+v", j.Header)
+			}
+			if _, err := VerifyJWS(string(enc), wrongKey); !errors.Is(err, ErrSignatureInvalid) {
+				t.Errorf("VerifyJWS() error = %v using wrong key, want %v", err, ErrSignatureInvalid)
+			}
+			ks := NewMemoryKeySet()
+			if err := ks.Add("manifest", public); err != nil {
+				t.Fatalf("Failed to add key: %s", err.Error())
+			}
+			if err := j.VerifyWithKeySet(ks); err != nil {
+				t.Errorf("Failed to verify JWS with key set: %s", err.Error())
+			}
+			// Only JWS of type JWT can be decoded as JWT
+			if _, err := Decode(string(enc)); err == nil {
+				t.Error("Decode() accepted JWS that is not a JWT")
+			}
+		})
+	}
+}
+
+func TestJWS_Verify(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+
+	// JWTs are JWS containing JSON and can be decoded as such
+	enc, err := s.Sign(map[string]interface{}{"sub": "test"})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+	j, err := DecodeJWS(string(enc))
+	if err != nil {
+		t.Fatalf("Failed to decode JWT as JWS: %s", err.Error())
+	}
+	if j.Header.Typ != "JWT" || string(j.Payload) != `{"sub":"test"}` {
+		t.Errorf("DecodeJWS() = %+v", j)
+	}
+	if err := j.Verify(public); err != nil {
+		t.Errorf("Failed to verify JWT as JWS: %s", err.Error())
+	}
+
+	// Changes made after decoding are not taken into account while JWS created manually are encoded for verification
+	j.Payload = []byte(`{"sub":"admin"}`)
+	if err := j.Verify(public); err != nil {
+		t.Errorf("Failed to verify decoded JWS after changing payload: %s", err.Error())
+	}
+	manual := JWS{Header: j.Header, Payload: j.Payload, Signature: j.Signature}
+	if err := manual.Verify(public); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("JWS.Verify() error = %v for modified payload, want %v", err, ErrSignatureInvalid)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"Sections", "eyJhbGciOiJFZERTQSJ9.AA"},
+		{"Header", "!.AA.AA"},
+		{"Payload", "eyJhbGciOiJFZERTQSJ9.!.AA"},
+		{"Signature", "eyJhbGciOiJFZERTQSJ9.AA.!"},
+		{"EmptySignature", "eyJhbGciOiJFZERTQSJ9.AA."},
+		// Header {"alg":"HS256","b64":false,"crit":["b64"]}
+		{"Unencoded", "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19.AA.AA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeJWS(tt.token); !errors.Is(err, ErrMalformed) {
+				t.Errorf("DecodeJWS() error = %v, want %v", err, ErrMalformed)
+			}
+		})
+	}
+}
@@ -1,14 +1,24 @@
 package jwt
 
 import (
+	"sync"
+
 	"golang.org/x/crypto/ed25519"
 )
 
-var privateKey ed25519.PrivateKey
-var setup = false
+var defaultSigner *Signer
+var defaultSignerLock sync.RWMutex
 
-// Setup initializes the package for encoding by setting the public and private key
+// Setup initializes the package for encoding by setting the private key used by Encode
+// It is safe to call Setup while other goroutines are encoding tokens
 func Setup(key ed25519.PrivateKey) {
-	privateKey = key
-	setup = true
+	defaultSignerLock.Lock()
+	defaultSigner = &Signer{key: key, method: SigningMethodEdDSA}
+	defaultSignerLock.Unlock()
+}
+
+func getDefaultSigner() *Signer {
+	defaultSignerLock.RLock()
+	defer defaultSignerLock.RUnlock()
+	return defaultSigner
 }
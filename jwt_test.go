@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -19,18 +20,25 @@ func TestSetup(t *testing.T) {
 	}
 	Setup(key)
 
-	if !reflect.DeepEqual(privateKey, key) {
-		t.Fatalf("Private key was not set by setup")
+	s := getDefaultSigner()
+	if s == nil {
+		t.Fatalf("Default signer was not set by setup")
 	}
-	if !setup {
-		t.Fatalf("Setup was not set to true by setup")
+	if !reflect.DeepEqual(s.key, key) {
+		t.Fatalf("Private key was not set by setup")
 	}
-	privateKey = nil
-	setup = false
+	resetDefaultSigner()
+}
+
+// resetDefaultSigner removes the key set by Setup
+func resetDefaultSigner() {
+	defaultSignerLock.Lock()
+	defaultSigner = nil
+	defaultSignerLock.Unlock()
 }
 
 func TestEnc(t *testing.T) {
-	jwt := JWT{Header{Typ: "JWT", Alg: "EdDSA"}, map[string]interface{}{"test1": "Hello world", "test2": "Testing", "exp": time.Now().Add(10 * time.Minute), "nbf": time.Now()}, nil}
+	jwt := JWT{Header{Typ: "JWT", Alg: "EdDSA"}, map[string]interface{}{"test1": "Hello world", "test2": "Testing", "exp": time.Now().Add(10 * time.Minute), "nbf": time.Now()}, nil, nil}
 	_, err := jwt.Encode()
 	if err == nil || err.Error() != "call setup with private key first" {
 		t.Fatalf("Encode should fail due to missing private key")
@@ -49,7 +57,7 @@ func TestEnc(t *testing.T) {
 	token = enc
 	jwt = JWT{Header{Typ: "JWT", Alg: "EdDSA"}, func(test int) bool {
 		return test == 20
-	}, nil}
+	}, nil, nil}
 	_, err = jwt.Encode()
 	if err == nil {
 		t.Fatal("JSON encoder accepted function")
@@ -76,21 +84,21 @@ func TestValidation(t *testing.T) {
 	}
 
 	// Check that an invalid public key does not cause a panic
-	token := JWT{Header{}, nil, nil}
+	token := JWT{Header{}, nil, nil, nil}
 	err = token.Validate([]byte("Hello world!"))
 	if err == nil || err.Error() != "key is not a valid public key" {
 		t.Fatalf("Failed to detect public key is not valid")
 	}
 
 	// Check that validation fails if token is not JWT
-	token = JWT{Header{Typ: "token", Alg: "none"}, nil, nil}
+	token = JWT{Header{Typ: "token", Alg: "none"}, nil, nil, nil}
 	err = token.Validate(publicKey)
 	if err == nil || err.Error() != "header indicates token is not JWT" {
 		t.Fatalf("Failed to detect token is not JWT")
 	}
 
 	// Check that validation fails for unsupported algorithms
-	token = JWT{Header{Typ: "JWT", Alg: "none"}, nil, nil}
+	token = JWT{Header{Typ: "JWT", Alg: "none"}, nil, nil, nil}
 	err = token.Validate(publicKey)
 	if err == nil {
 		t.Fatalf("Failed to detect unsupported algorithm")
@@ -106,20 +114,20 @@ func TestValidation(t *testing.T) {
 		t.Fatalf("Validating succeded with invalid public key")
 	}
 
-	// Check that validation succeeds with unsupported content
-	token = JWT{Header{Typ: "JWT", Alg: "EdDSA"}, "Hello world!", nil}
+	// Check that validation fails for tokens that have not been signed
+	token = JWT{Header{Typ: "JWT", Alg: "EdDSA"}, "Hello world!", nil, nil}
 	err = token.Validate(publicKey)
-	if err != nil {
-		t.Fatalf("Validation with unsupported content failed instead of ignoring content: %s", err.Error())
+	if !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("Validation succeeded for token without hash: %v", err)
 	}
-	token = JWT{Header{Typ: "JWT", Alg: "EdDSA"}, 1234567890, nil}
+	token = JWT{Header{Typ: "JWT", Alg: "EdDSA"}, 1234567890, []byte{}, nil}
 	err = token.Validate(publicKey)
-	if err != nil {
-		t.Fatalf("Validation with unsupported content failed instead of ignoring content: %s", err.Error())
+	if !errors.Is(err, ErrSignatureInvalid) {
+		t.Fatalf("Validation succeeded for token with empty hash: %v", err)
 	}
 
 	// Check validation of expiry
-	expiredToken := JWT{Header{Typ: "JWT", Alg: "EdDSA"}, map[string]interface{}{"exp": time.Now().Add(-10 * time.Minute).UTC().Unix()}, nil}
+	expiredToken := JWT{Header{Typ: "JWT", Alg: "EdDSA"}, map[string]interface{}{"exp": time.Now().Add(-10 * time.Minute).UTC().Unix()}, nil, nil}
 	enc, err := expiredToken.Encode()
 	if err != nil {
 		t.Fatalf("Failed to encode token to validate expiry: %s", err.Error())
@@ -134,7 +142,7 @@ func TestValidation(t *testing.T) {
 	}
 
 	// Check validation of not before
-	nbfToken := JWT{Header{Typ: "JWT", Alg: "EdDSA"}, map[string]interface{}{"nbf": time.Now().Add(10 * time.Minute).UTC().Unix()}, nil}
+	nbfToken := JWT{Header{Typ: "JWT", Alg: "EdDSA"}, map[string]interface{}{"nbf": time.Now().Add(10 * time.Minute).UTC().Unix()}, nil, nil}
 	enc, err = nbfToken.Encode()
 	if err != nil {
 		t.Fatalf("Failed to encode token to validate not before: %s", err.Error())
@@ -151,7 +159,7 @@ func TestValidation(t *testing.T) {
 	// Check error handling for function as content
 	functionToken := JWT{Header{Typ: "JWT", Alg: "EdDSA"}, func(test int) bool {
 		return test == 20
-	}, nil}
+	}, nil, nil}
 	err = functionToken.Validate(publicKey)
 	if err == nil {
 		t.Fatal("JSON encoder accepted function")
@@ -165,11 +173,7 @@ func TestValidation(t *testing.T) {
 		42,
 		true,
 	}
-	structToken := JWT{Header{Typ: "JWT", Alg: "EdDSA"}, testStruct, nil}
-	err = structToken.Validate(publicKey)
-	if err != nil {
-		t.Errorf("Failed to validate a token generated using a struct: %s", err.Error())
-	}
+	structToken := JWT{Header{Typ: "JWT", Alg: "EdDSA"}, testStruct, nil, nil}
 	encodedStructToken, err := structToken.Encode()
 	if err != nil {
 		t.Errorf("Failed to encode struct token: %s", err.Error())
@@ -183,3 +187,32 @@ func TestValidation(t *testing.T) {
 		t.Errorf("Failed to validate decoded struct token: %s", err.Error())
 	}
 }
+
+func TestValidationUnsortedContent(t *testing.T) {
+	public, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+
+	// Simulate a token generated by a third party with keys that are not sorted alphabetically and additional whitespace
+	header := b64encode([]byte(`{"alg": "EdDSA", "typ": "JWT"}`))
+	content := b64encode([]byte(`{"sub": "1234567890", "name": "John Doe", "admin": true}`))
+	data := join(header, content)
+	enc := string(join(data, b64encode(ed25519.Sign(priv, data))))
+
+	dec, err := Decode(enc)
+	if err != nil {
+		t.Fatalf("Failed to decode third party token: %s", err.Error())
+	}
+	err = dec.Validate(public)
+	if err != nil {
+		t.Fatalf("Failed to validate third party token: %s", err.Error())
+	}
+
+	// Changes to the content after decoding must not affect validation
+	dec.Content.(map[string]interface{})["admin"] = false
+	err = dec.Validate(public)
+	if err != nil {
+		t.Fatalf("Failed to validate third party token after modifying content: %s", err.Error())
+	}
+}
@@ -0,0 +1,111 @@
+// Package jwtgrpc provides gRPC server interceptors that authenticate calls using bearer tokens passed as authorization metadata
+package jwtgrpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	jwt "github.com/fossoreslp/go-jwt-ed25519"
+	"github.com/fossoreslp/go-jwt-ed25519/internal/autherr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Interceptor verifies the bearer token of each call and only passes calls carrying a valid token on to the handler
+// The verified token is stored in the context of the call and can be retrieved using jwt.FromContext
+// An Interceptor is immutable and may therefore be used by multiple goroutines at once
+type Interceptor struct {
+	verifier *jwt.Verifier
+	public   map[string]bool
+}
+
+// Option configures an Interceptor
+type Option func(*Interceptor)
+
+// WithPublicMethods allows calling methods without a token
+// Methods are identified by their full name like /grpc.health.v1.Health/Check and tokens passed to them are ignored
+func WithPublicMethods(methods ...string) Option {
+	return func(i *Interceptor) {
+		for _, m := range methods {
+			i.public[m] = true
+		}
+	}
+}
+
+// New returns an Interceptor verifying tokens using v
+func New(v *jwt.Verifier, opts ...Option) (*Interceptor, error) {
+	if v == nil {
+		return nil, errors.New("verifier is required")
+	}
+	i := &Interceptor{verifier: v, public: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i, nil
+}
+
+// Unary returns a grpc.UnaryServerInterceptor that verifies the token of each call
+// Calls without a valid token fail with codes.Unauthenticated
+func (i *Interceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if i.public[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		ctx, err := i.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Stream returns a grpc.StreamServerInterceptor that verifies the token of each stream before it is passed to the handler
+// Streams without a valid token fail with codes.Unauthenticated
+func (i *Interceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if i.public[info.FullMethod] {
+			return handler(srv, ss)
+		}
+		ctx, err := i.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, serverStream{ss, ctx})
+	}
+}
+
+// authenticate verifies the token in the authorization metadata of ctx and returns a copy of ctx carrying the verified token
+func (i *Interceptor) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "authorization metadata is missing")
+	}
+	if len(values) > 1 {
+		return nil, status.Error(codes.Unauthenticated, "authorization metadata must only contain one token")
+	}
+	scheme, token, _ := strings.Cut(values[0], " ")
+	token = strings.TrimSpace(token)
+	if !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, status.Error(codes.Unauthenticated, "authorization metadata does not contain a bearer token")
+	}
+	t, err := i.verifier.VerifyToken(token)
+	if err != nil {
+		// The error itself may contain details like the key ID supplied by the client
+		return nil, status.Error(codes.Unauthenticated, "invalid token: "+autherr.Describe(err))
+	}
+	return jwt.NewContext(ctx, t), nil
+}
+
+// serverStream replaces the context of a grpc.ServerStream with one carrying the verified token
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,181 @@
+package jwtgrpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	jwt "github.com/fossoreslp/go-jwt-ed25519"
+	"golang.org/x/crypto/ed25519"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// serve starts a health server using i on an in-process listener and returns a client connected to it
+// The subject of the verified token of the last call is sent on subjects
+func serve(t *testing.T, i *Interceptor, subjects chan<- string) healthpb.HealthClient {
+	record := func(ctx context.Context) {
+		tok, _ := jwt.FromContext(ctx)
+		subjects <- tok.RegisteredClaims().Subject
+	}
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(i.Unary(), func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			record(ctx)
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(i.Stream(), func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			record(ss.Context())
+			return handler(srv, ss)
+		}),
+	)
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to connect to server: %s", err.Error())
+	}
+	t.Cleanup(func() { conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestInterceptor(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := jwt.NewSignerWithKeyID(key, "key")
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	unknown, err := jwt.NewSignerWithKeyID(key, "unknown-key-id")
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	ks := jwt.NewMemoryKeySet()
+	err = ks.Add("key", public)
+	if err != nil {
+		t.Fatalf("Failed to add key: %s", err.Error())
+	}
+	v, err := jwt.NewVerifierWithKeySet(ks)
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %s", err.Error())
+	}
+	i, err := New(v)
+	if err != nil {
+		t.Fatalf("Failed to create interceptor: %s", err.Error())
+	}
+	subjects := make(chan string, 1)
+	client := serve(t, i, subjects)
+
+	valid, err := s.Sign(jwt.RegisteredClaims{Subject: "1234"})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+	expired, err := s.Sign(jwt.RegisteredClaims{Subject: "1234", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+	unknownKey, err := unknown.Sign(jwt.RegisteredClaims{Subject: "1234"})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+	tests := []struct {
+		name string
+		md   []string
+		want codes.Code
+	}{
+		{"Valid", []string{"authorization", "Bearer " + string(valid)}, codes.OK},
+		{"Missing", nil, codes.Unauthenticated},
+		{"Expired", []string{"authorization", "Bearer " + string(expired)}, codes.Unauthenticated},
+		{"Malformed", []string{"authorization", "Bearer test"}, codes.Unauthenticated},
+		{"UnknownKey", []string{"authorization", "Bearer " + string(unknownKey)}, codes.Unauthenticated},
+		{"OtherScheme", []string{"authorization", "Basic dXNlcjpwYXNz"}, codes.Unauthenticated},
+		{"Multiple", []string{"authorization", "Bearer " + string(valid), "authorization", "Bearer " + string(valid)}, codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			ctx = metadata.AppendToOutgoingContext(ctx, tt.md...)
+
+			_, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+			if status.Code(err) != tt.want {
+				t.Errorf("Check() error = %v, want code %s", err, tt.want)
+			}
+			// Details of the error like the key ID are not sent to the client
+			if strings.Contains(status.Convert(err).Message(), "unknown-key-id") {
+				t.Errorf("Check() error = %v, contains key ID of the token", err)
+			}
+			if tt.want == codes.OK {
+				if sub := <-subjects; sub != "1234" {
+					t.Errorf("Check() handler received subject %q, want 1234", sub)
+				}
+			}
+
+			stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+			if err != nil {
+				t.Fatalf("Failed to start stream: %s", err.Error())
+			}
+			_, err = stream.Recv()
+			if status.Code(err) != tt.want {
+				t.Errorf("Watch() error = %v, want code %s", err, tt.want)
+			}
+			if tt.want == codes.OK {
+				if sub := <-subjects; sub != "1234" {
+					t.Errorf("Watch() handler received subject %q, want 1234", sub)
+				}
+			}
+		})
+	}
+}
+
+func TestWithPublicMethods(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	v, err := jwt.NewVerifier(public)
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %s", err.Error())
+	}
+	i, err := New(v, WithPublicMethods("/grpc.health.v1.Health/Check"))
+	if err != nil {
+		t.Fatalf("Failed to create interceptor: %s", err.Error())
+	}
+	subjects := make(chan string, 1)
+	client := serve(t, i, subjects)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Errorf("Check() error = %v on public method", err)
+	}
+	if sub := <-subjects; sub != "" {
+		t.Errorf("Check() handler received subject %q without token", sub)
+	}
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Failed to start stream: %s", err.Error())
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Watch() error = %v, want code %s", err, codes.Unauthenticated)
+	}
+
+	if _, err := New(nil); err == nil {
+		t.Error("New() accepted missing verifier")
+	}
+}
@@ -0,0 +1,136 @@
+// Package jwthttp provides a net/http middleware that authenticates requests using bearer tokens as defined in RFC 6750
+package jwthttp
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	jwt "github.com/fossoreslp/go-jwt-ed25519"
+	"github.com/fossoreslp/go-jwt-ed25519/internal/autherr"
+)
+
+// Middleware verifies the bearer token of each request and only passes requests carrying a valid token to the next handler
+// The verified token is stored in the context of the request and can be retrieved using jwt.FromContext
+// A Middleware is immutable and may therefore be used by multiple goroutines at once
+type Middleware struct {
+	verifier *jwt.Verifier
+	cookie   string
+	query    string
+	realm    string
+}
+
+// Option configures a Middleware
+type Option func(*Middleware)
+
+// WithCookie additionally reads the token from the cookie called name
+func WithCookie(name string) Option {
+	return func(m *Middleware) {
+		m.cookie = name
+	}
+}
+
+// WithQuery additionally reads the token from the query parameter called name
+// RFC 6750 uses access_token as name, but tokens in URLs are likely to be logged, so this should only be used when no other method is available
+func WithQuery(name string) Option {
+	return func(m *Middleware) {
+		m.query = name
+	}
+}
+
+// WithRealm sets the realm included in the WWW-Authenticate header of error responses
+func WithRealm(realm string) Option {
+	return func(m *Middleware) {
+		m.realm = realm
+	}
+}
+
+// New returns a Middleware verifying tokens using v
+// By default tokens are only read from the Authorization header
+func New(v *jwt.Verifier, opts ...Option) (*Middleware, error) {
+	if v == nil {
+		return nil, errors.New("verifier is required")
+	}
+	m := &Middleware{verifier: v}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if strings.ContainsAny(m.realm, "\"\\") {
+		return nil, errors.New("realm must not contain quotes or backslashes")
+	}
+	return m, nil
+}
+
+// Handler returns a http.Handler that verifies the token of each request before passing it to next
+// Requests without a valid token are answered with 401 Unauthorized and requests using more than one method to transmit the token with 400 Bad Request
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := m.token(r)
+		if err != nil {
+			m.fail(w, http.StatusBadRequest, "invalid_request", err.Error())
+			return
+		}
+		if token == "" {
+			m.fail(w, http.StatusUnauthorized, "", "")
+			return
+		}
+		t, err := m.verifier.VerifyToken(token)
+		if err != nil {
+			m.fail(w, http.StatusUnauthorized, "invalid_token", autherr.Describe(err))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(jwt.NewContext(r.Context(), t)))
+	})
+}
+
+// token returns the token transmitted with r or an empty string if there is none
+// An error is returned when more than one method is used as RFC 6750 Section 2 forbids this
+func (m *Middleware) token(r *http.Request) (string, error) {
+	var tokens []string
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		scheme, token, _ := strings.Cut(auth, " ")
+		// Other authentication schemes are treated like missing authentication
+		if strings.EqualFold(scheme, "Bearer") {
+			token = strings.TrimSpace(token)
+			if token == "" {
+				return "", errors.New("the authorization header does not contain a token")
+			}
+			tokens = append(tokens, token)
+		}
+	}
+	if m.cookie != "" {
+		if c, err := r.Cookie(m.cookie); err == nil && c.Value != "" {
+			tokens = append(tokens, c.Value)
+		}
+	}
+	if m.query != "" {
+		if token := r.URL.Query().Get(m.query); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	switch len(tokens) {
+	case 0:
+		return "", nil
+	case 1:
+		return tokens[0], nil
+	}
+	return "", errors.New("the token must only be transmitted using one method")
+}
+
+// fail writes an error response with a WWW-Authenticate header as defined in RFC 6750 Section 3
+// The error code and description are omitted if code is empty, which is used when the request did not contain a token
+func (m *Middleware) fail(w http.ResponseWriter, status int, code, description string) {
+	var params []string
+	if m.realm != "" {
+		params = append(params, `realm="`+m.realm+`"`)
+	}
+	if code != "" {
+		params = append(params, `error="`+code+`"`, `error_description="`+description+`"`)
+	}
+	challenge := "Bearer"
+	if len(params) > 0 {
+		challenge += " " + strings.Join(params, ", ")
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, http.StatusText(status), status)
+}
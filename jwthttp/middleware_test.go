@@ -0,0 +1,111 @@
+package jwthttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jwt "github.com/fossoreslp/go-jwt-ed25519"
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestMiddleware_Handler(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := jwt.NewSigner(key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	v, err := jwt.NewVerifier(public, jwt.WithIssuer("issuer"))
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %s", err.Error())
+	}
+	m, err := New(v, WithCookie("session"), WithQuery("access_token"), WithRealm("example"))
+	if err != nil {
+		t.Fatalf("Failed to create middleware: %s", err.Error())
+	}
+	sign := func(claims jwt.RegisteredClaims) string {
+		enc, err := s.Sign(claims)
+		if err != nil {
+			t.Fatalf("Failed to sign token: %s", err.Error())
+		}
+		return string(enc)
+	}
+	valid := sign(jwt.RegisteredClaims{Issuer: "issuer", Subject: "1234"})
+	expired := sign(jwt.RegisteredClaims{Issuer: "issuer", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))})
+	wrongIssuer := sign(jwt.RegisteredClaims{Issuer: "other"})
+
+	h := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tok, ok := jwt.FromContext(r.Context())
+		if !ok {
+			t.Error("Handler was called without token in context")
+		}
+		w.Write([]byte(tok.RegisteredClaims().Subject))
+	}))
+
+	tests := []struct {
+		name      string
+		header    string
+		cookie    string
+		query     string
+		status    int
+		challenge string
+	}{
+		{"Header", "Bearer " + valid, "", "", http.StatusOK, ""},
+		{"HeaderLowercase", "bearer " + valid, "", "", http.StatusOK, ""},
+		{"Cookie", "", valid, "", http.StatusOK, ""},
+		{"Query", "", "", valid, http.StatusOK, ""},
+		{"Missing", "", "", "", http.StatusUnauthorized, `Bearer realm="example"`},
+		{"OtherScheme", "Basic dXNlcjpwYXNz", "", "", http.StatusUnauthorized, `Bearer realm="example"`},
+		{"EmptyBearer", "Bearer ", "", "", http.StatusBadRequest, `Bearer realm="example", error="invalid_request", error_description="the authorization header does not contain a token"`},
+		{"MultipleMethods", "Bearer " + valid, "", valid, http.StatusBadRequest, `Bearer realm="example", error="invalid_request", error_description="the token must only be transmitted using one method"`},
+		{"Malformed", "Bearer test", "", "", http.StatusUnauthorized, `Bearer realm="example", error="invalid_token", error_description="the token is malformed"`},
+		{"Expired", "Bearer " + expired, "", "", http.StatusUnauthorized, `Bearer realm="example", error="invalid_token", error_description="the token has expired"`},
+		{"WrongIssuer", "", wrongIssuer, "", http.StatusUnauthorized, `Bearer realm="example", error="invalid_token", error_description="claim does not have the expected value: iss"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: "session", Value: tt.cookie})
+			}
+			if tt.query != "" {
+				r.URL.RawQuery = "access_token=" + tt.query
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Errorf("Handler() status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("WWW-Authenticate"); got != tt.challenge {
+				t.Errorf("Handler() WWW-Authenticate = %s, want %s", got, tt.challenge)
+			}
+			if tt.status == http.StatusOK && w.Body.String() != "1234" {
+				t.Errorf("Handler() body = %s, want subject of token", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	v, err := jwt.NewVerifier(public)
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %s", err.Error())
+	}
+	if _, err := New(nil); err == nil {
+		t.Error("New() accepted missing verifier")
+	}
+	if _, err := New(v, WithRealm(`"example"`)); err == nil {
+		t.Error("New() accepted realm containing quotes")
+	}
+}
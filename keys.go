@@ -0,0 +1,196 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+// ParsePrivateKeyPEM parses an Ed25519 private key from a PEM block of type PRIVATE KEY containing a PKCS #8 key as generated by `openssl genpkey -algorithm ed25519`
+func ParsePrivateKeyPEM(data []byte) (ed25519.PrivateKey, error) {
+	block, err := decodePEM(data, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	k, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New(keyTypeName(key) + " key is not an Ed25519 key")
+	}
+	return k, nil
+}
+
+// ParsePublicKeyPEM parses an Ed25519 public key from a PEM block of type PUBLIC KEY containing a PKIX key as generated by `openssl pkey -pubout`
+func ParsePublicKeyPEM(data []byte) (ed25519.PublicKey, error) {
+	block, err := decodePEM(data, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	k, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New(keyTypeName(key) + " key is not an Ed25519 key")
+	}
+	return k, nil
+}
+
+// MarshalPrivateKeyPEM encodes key as PKCS #8 in a PEM block of type PRIVATE KEY
+func MarshalPrivateKeyPEM(key ed25519.PrivateKey) ([]byte, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, errors.New("key is not a valid private key")
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// MarshalPublicKeyPEM encodes key as PKIX in a PEM block of type PUBLIC KEY
+func MarshalPublicKeyPEM(key ed25519.PublicKey) ([]byte, error) {
+	if len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("key is not a valid public key")
+	}
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// ParsePrivateKey returns the private key for raw which may either be a 32 byte seed or a 64 byte private key as used by this package
+// The public key contained in a 64 byte private key has to match the seed
+func ParsePrivateKey(raw []byte) (ed25519.PrivateKey, error) {
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		key := ed25519.NewKeyFromSeed(raw[:ed25519.SeedSize])
+		if !key.Public().(ed25519.PublicKey).Equal(ed25519.PublicKey(raw[ed25519.SeedSize:])) {
+			return nil, errors.New("public key does not match seed")
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("private key has to be %d or %d bytes long, got %d", ed25519.SeedSize, ed25519.PrivateKeySize, len(raw))
+}
+
+// ParsePublicKey returns the public key for raw which has to be 32 bytes long
+func ParsePublicKey(raw []byte) (ed25519.PublicKey, error) {
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key has to be %d bytes long, got %d", ed25519.PublicKeySize, len(raw))
+	}
+	key := make(ed25519.PublicKey, ed25519.PublicKeySize)
+	copy(key, raw)
+	return key, nil
+}
+
+// ParsePrivateKeyBase64 parses a seed or private key encoded using standard or URL safe base64 with or without padding
+func ParsePrivateKeyBase64(s string) (ed25519.PrivateKey, error) {
+	raw, err := decodeBase64(s)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePrivateKey(raw)
+}
+
+// ParsePublicKeyBase64 parses a public key encoded using standard or URL safe base64 with or without padding
+func ParsePublicKeyBase64(s string) (ed25519.PublicKey, error) {
+	raw, err := decodeBase64(s)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKey(raw)
+}
+
+// ParsePrivateKeyHex parses a seed or private key encoded as hex
+func ParsePrivateKeyHex(s string) (ed25519.PrivateKey, error) {
+	raw, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	return ParsePrivateKey(raw)
+}
+
+// ParsePublicKeyHex parses a public key encoded as hex
+func ParsePublicKeyHex(s string) (ed25519.PublicKey, error) {
+	raw, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKey(raw)
+}
+
+// MarshalPrivateKeyBase64 encodes the seed of key using standard base64
+func MarshalPrivateKeyBase64(key ed25519.PrivateKey) (string, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return "", errors.New("key is not a valid private key")
+	}
+	return base64.StdEncoding.EncodeToString(key.Seed()), nil
+}
+
+// MarshalPublicKeyBase64 encodes key using standard base64
+func MarshalPublicKeyBase64(key ed25519.PublicKey) (string, error) {
+	if len(key) != ed25519.PublicKeySize {
+		return "", errors.New("key is not a valid public key")
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// MarshalPrivateKeyHex encodes the seed of key as hex
+func MarshalPrivateKeyHex(key ed25519.PrivateKey) (string, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return "", errors.New("key is not a valid private key")
+	}
+	return hex.EncodeToString(key.Seed()), nil
+}
+
+// MarshalPublicKeyHex encodes key as hex
+func MarshalPublicKeyHex(key ed25519.PublicKey) (string, error) {
+	if len(key) != ed25519.PublicKeySize {
+		return "", errors.New("key is not a valid public key")
+	}
+	return hex.EncodeToString(key), nil
+}
+
+func decodePEM(data []byte, typ string) (*pem.Block, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	if block.Type != typ {
+		return nil, fmt.Errorf("PEM block has type %s, expected %s", block.Type, typ)
+	}
+	return block, nil
+}
+
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(strings.TrimSpace(s), "=")
+	if strings.ContainsAny(s, "-_") {
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	return base64.RawStdEncoding.DecodeString(s)
+}
+
+func keyTypeName(key interface{}) string {
+	switch key.(type) {
+	case *rsa.PrivateKey, *rsa.PublicKey:
+		return "RSA"
+	case *ecdsa.PrivateKey, *ecdsa.PublicKey:
+		return "ECDSA"
+	}
+	return fmt.Sprintf("%T", key)
+}
@@ -0,0 +1,109 @@
+package jwt
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// KeySet provides the public keys used to validate tokens
+type KeySet interface {
+	// Keys returns all keys that may have been used to sign a token with header h
+	// An error is returned when no key is suitable, e.g. because the key ID is unknown
+	Keys(h Header) ([]crypto.PublicKey, error)
+}
+
+// MemoryKeySet is a KeySet holding keys in memory that are selected by their key ID
+// Tokens without a key ID are validated using the default key and, if enabled, by trying all keys in the set
+// A MemoryKeySet may be used and modified by multiple goroutines at once
+type MemoryKeySet struct {
+	lock   sync.RWMutex
+	keys   map[string]crypto.PublicKey
+	def    crypto.PublicKey
+	tryAll bool
+}
+
+// NewMemoryKeySet returns an empty MemoryKeySet
+func NewMemoryKeySet() *MemoryKeySet {
+	return &MemoryKeySet{keys: make(map[string]crypto.PublicKey)}
+}
+
+// Add adds key to the set using key ID kid and replaces any key previously added with the same key ID
+func (s *MemoryKeySet) Add(kid string, key crypto.PublicKey) error {
+	if kid == "" {
+		return errors.New("empty key IDs are not supported")
+	}
+	err := checkPublicKey(key)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	s.keys[kid] = key
+	s.lock.Unlock()
+	return nil
+}
+
+// Remove removes the key with key ID kid from the set
+func (s *MemoryKeySet) Remove(kid string) {
+	s.lock.Lock()
+	delete(s.keys, kid)
+	s.lock.Unlock()
+}
+
+// SetDefault sets the key used for tokens without a key ID
+// Use nil to remove the default key
+func (s *MemoryKeySet) SetDefault(key crypto.PublicKey) error {
+	if key != nil {
+		err := checkPublicKey(key)
+		if err != nil {
+			return err
+		}
+	}
+	s.lock.Lock()
+	s.def = key
+	s.lock.Unlock()
+	return nil
+}
+
+// SetTryAll enables or disables trying all keys in the set for tokens without a key ID
+func (s *MemoryKeySet) SetTryAll(tryAll bool) {
+	s.lock.Lock()
+	s.tryAll = tryAll
+	s.lock.Unlock()
+}
+
+// Keys returns the key with the key ID of the token or, if the token does not contain one, the default key and all other keys if enabled
+func (s *MemoryKeySet) Keys(h Header) ([]crypto.PublicKey, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if h.Kid != "" {
+		key, ok := s.keys[h.Kid]
+		if !ok {
+			return nil, fmt.Errorf("%w %s", ErrUnknownKey, h.Kid)
+		}
+		return []crypto.PublicKey{key}, nil
+	}
+	var keys []crypto.PublicKey
+	if s.def != nil {
+		keys = append(keys, s.def)
+	}
+	if s.tryAll {
+		for _, key := range s.keys {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("token does not contain a key ID and no default key is set")
+	}
+	return keys, nil
+}
+
+// singleKey is a KeySet always returning the same key regardless of the key ID
+type singleKey struct {
+	key crypto.PublicKey
+}
+
+func (k singleKey) Keys(Header) ([]crypto.PublicKey, error) {
+	return []crypto.PublicKey{k.key}, nil
+}
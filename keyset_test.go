@@ -0,0 +1,119 @@
+package jwt
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestMemoryKeySet(t *testing.T) {
+	public1, key1, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	public2, key2, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	_, key3, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	ks := NewMemoryKeySet()
+	if err := ks.Add("key1", public1); err != nil {
+		t.Fatalf("Failed to add key: %s", err.Error())
+	}
+	if err := ks.Add("key2", public2); err != nil {
+		t.Fatalf("Failed to add key: %s", err.Error())
+	}
+	if err := ks.Add("", public2); err == nil {
+		t.Error("MemoryKeySet.Add() accepted empty key ID")
+	}
+	if err := ks.Add("key3", ed25519.PublicKey("test")); err == nil {
+		t.Error("MemoryKeySet.Add() accepted invalid public key")
+	}
+	if err := ks.SetDefault(ed25519.PublicKey("test")); err == nil {
+		t.Error("MemoryKeySet.SetDefault() accepted invalid public key")
+	}
+
+	sign := func(key ed25519.PrivateKey, kid string) JWT {
+		var s *Signer
+		var err error
+		if kid == "" {
+			s, err = NewSigner(key)
+		} else {
+			s, err = NewSignerWithKeyID(key, kid)
+		}
+		if err != nil {
+			t.Fatalf("Failed to create signer: %s", err.Error())
+		}
+		enc, err := s.Sign(map[string]interface{}{"test": "key set"})
+		if err != nil {
+			t.Fatalf("Failed to sign token: %s", err.Error())
+		}
+		dec, err := Decode(string(enc))
+		if err != nil {
+			t.Fatalf("Failed to decode token: %s", err.Error())
+		}
+		return dec
+	}
+
+	tests := []struct {
+		name    string
+		token   JWT
+		setup   func()
+		wantErr bool
+	}{
+		{"KeyID", sign(key2, "key2"), func() {}, false},
+		{"WrongKeyForKeyID", sign(key1, "key2"), func() {}, true},
+		{"UnknownKeyID", sign(key3, "key3"), func() {}, true},
+		{"NoKeyIDWithoutDefault", sign(key1, ""), func() {}, true},
+		{"NoKeyIDWithDefault", sign(key1, ""), func() { ks.SetDefault(public1) }, false},
+		{"NoKeyIDWrongDefault", sign(key2, ""), func() {}, true},
+		{"NoKeyIDTryAll", sign(key2, ""), func() { ks.SetTryAll(true) }, false},
+		{"NoKeyIDTryAllWithoutDefault", sign(key2, ""), func() { ks.SetDefault(nil) }, false},
+		{"NoKeyIDTryAllUnknownKey", sign(key3, ""), func() {}, true},
+		{"RemovedKeyID", sign(key2, "key2"), func() { ks.Remove("key2") }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			if err := tt.token.VerifyWithKeySet(ks); (err != nil) != tt.wantErr {
+				t.Errorf("JWT.VerifyWithKeySet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewVerifierWithKeySet(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	if _, err := NewVerifierWithKeySet(nil); err == nil {
+		t.Error("NewVerifierWithKeySet() accepted nil key set")
+	}
+	ks := NewMemoryKeySet()
+	if err := ks.Add("key", public); err != nil {
+		t.Fatalf("Failed to add key: %s", err.Error())
+	}
+	v, err := NewVerifierWithKeySet(ks, WithRequiredClaims("sub"))
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %s", err.Error())
+	}
+	s, err := NewSignerWithKeyID(key, "key")
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	enc, err := s.Sign(RegisteredClaims{Subject: "test"})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+	if _, err := v.Verify(string(enc)); err != nil {
+		t.Errorf("Verifier.Verify() failed to verify token using key set: %s", err.Error())
+	}
+	ks.Remove("key")
+	if _, err := v.Verify(string(enc)); err == nil {
+		t.Error("Verifier.Verify() accepted token signed with removed key")
+	}
+}
@@ -0,0 +1,48 @@
+package jwt
+
+import (
+	"crypto"
+	"errors"
+	"strings"
+)
+
+// SignAndEncrypt signs content as JWT using s and encrypts the token for the recipient of e as nested JWT as defined in RFC 7519 Section 5.2
+// The content type of the encrypted token is set to JWT so recipients know it contains a signed token
+func SignAndEncrypt(content interface{}, s *Signer, e *Encrypter) ([]byte, error) {
+	signed, err := s.Sign(content)
+	if err != nil {
+		return nil, err
+	}
+	return e.Encrypt(signed, "", "JWT")
+}
+
+// DecryptAndVerify decrypts a nested JWT using key and returns the signed token it contains only if v considers it valid
+// An error is returned if the content type of the encrypted token is not JWT
+func DecryptAndVerify(token string, key crypto.PrivateKey, v *Verifier) (JWT, error) {
+	signed, err := decryptNested(token, key)
+	if err != nil {
+		return JWT{}, err
+	}
+	return v.Verify(signed)
+}
+
+// DecryptAndVerifyInto behaves like DecryptAndVerify but decodes the content of the signed token into T
+func DecryptAndVerifyInto[T any](token string, key crypto.PrivateKey, v *Verifier) (Token[T], error) {
+	signed, err := decryptNested(token, key)
+	if err != nil {
+		return Token[T]{}, err
+	}
+	return VerifyInto[T](v, signed)
+}
+
+// decryptNested decrypts token and returns the signed token it contains
+func decryptNested(token string, key crypto.PrivateKey) (string, error) {
+	h, signed, err := Decrypt(token, key)
+	if err != nil {
+		return "", err
+	}
+	if !strings.EqualFold(h.Cty, "JWT") {
+		return "", malformed(errors.New("header indicates token does not contain a nested JWT"))
+	}
+	return string(signed), nil
+}
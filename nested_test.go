@@ -0,0 +1,102 @@
+package jwt
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestSignAndEncrypt(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	recipient, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	e, err := NewEncrypter(recipient.PublicKey(), "ECDH-ES+A256KW", "A256GCM")
+	if err != nil {
+		t.Fatalf("Failed to create encrypter: %s", err.Error())
+	}
+	v, err := NewVerifier(public, WithIssuer("issuer"))
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %s", err.Error())
+	}
+
+	enc, err := SignAndEncrypt(testClaims{RegisteredClaims: RegisteredClaims{Issuer: "issuer"}, Name: "user@example.com"}, s, e)
+	if err != nil {
+		t.Fatalf("Failed to sign and encrypt token: %s", err.Error())
+	}
+	h, _, err := Decrypt(string(enc), recipient)
+	if err != nil {
+		t.Fatalf("Failed to decrypt token: %s", err.Error())
+	}
+	if h.Cty != "JWT" {
+		t.Errorf("Encrypted token has content type %s, want JWT", h.Cty)
+	}
+	tok, err := DecryptAndVerify(string(enc), recipient, v)
+	if err != nil {
+		t.Fatalf("Failed to decrypt and verify token: %s", err.Error())
+	}
+	if tok.Content.(map[string]interface{})["name"] != "user@example.com" {
+		t.Errorf("DecryptAndVerify() content = %v", tok.Content)
+	}
+	typed, err := DecryptAndVerifyInto[testClaims](string(enc), recipient, v)
+	if err != nil {
+		t.Fatalf("Failed to decrypt and verify token: %s", err.Error())
+	}
+	if typed.Claims.Name != "user@example.com" {
+		t.Errorf("DecryptAndVerifyInto() claims = %+v", typed.Claims)
+	}
+
+	// Claims are validated after decryption
+	expired, err := SignAndEncrypt(RegisteredClaims{Issuer: "issuer", ExpiresAt: NewNumericDate(time.Now().Add(-time.Minute))}, s, e)
+	if err != nil {
+		t.Fatalf("Failed to sign and encrypt token: %s", err.Error())
+	}
+	if _, err := DecryptAndVerify(string(expired), recipient, v); !errors.Is(err, ErrExpired) {
+		t.Errorf("DecryptAndVerify() error = %v, want %v", err, ErrExpired)
+	}
+
+	// Tokens signed by another key are rejected
+	_, otherKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	other, err := NewSigner(otherKey)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	forged, err := SignAndEncrypt(RegisteredClaims{Issuer: "issuer"}, other, e)
+	if err != nil {
+		t.Fatalf("Failed to sign and encrypt token: %s", err.Error())
+	}
+	if _, err := DecryptAndVerify(string(forged), recipient, v); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("DecryptAndVerify() error = %v, want %v", err, ErrSignatureInvalid)
+	}
+
+	// Encrypted tokens without content type JWT are rejected
+	signed, err := s.Sign(RegisteredClaims{Issuer: "issuer"})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+	plain, err := e.Encrypt(signed, "", "")
+	if err != nil {
+		t.Fatalf("Failed to encrypt token: %s", err.Error())
+	}
+	if _, err := DecryptAndVerify(string(plain), recipient, v); !errors.Is(err, ErrMalformed) {
+		t.Errorf("DecryptAndVerify() error = %v, want %v", err, ErrMalformed)
+	}
+	if _, err := DecryptAndVerifyInto[testClaims](string(plain), recipient, v); !errors.Is(err, ErrMalformed) {
+		t.Errorf("DecryptAndVerifyInto() error = %v, want %v", err, ErrMalformed)
+	}
+}
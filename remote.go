@@ -0,0 +1,276 @@
+package jwt
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RemoteKeySet is a KeySet fetching keys from a JWKS published at a URL
+// Tokens may point to a different JWKS using their key URL (jku) which is only used when it is explicitly allowed
+// Keys are cached for as long as the Cache-Control header of the response allows and are fetched again when a token contains an unknown key ID
+// Tokens without a key ID are validated by trying all keys of the JWKS
+// A RemoteKeySet may be used by multiple goroutines at once
+type RemoteKeySet struct {
+	url      string
+	allowed  map[string]bool
+	client   *http.Client
+	ttl      time.Duration
+	interval time.Duration
+	now      func() time.Time
+
+	lock     sync.Mutex
+	cache    map[string]*remoteKeys
+	inflight map[string]*remoteFetch
+}
+
+// remoteKeys contains the keys fetched from a URL and is never modified once it has been cached
+// Keys is nil if no request has succeeded, yet, in which case err contains the reason the last request failed
+type remoteKeys struct {
+	keys    map[string]crypto.PublicKey
+	all     []crypto.PublicKey
+	fetched time.Time // Time of the last request, even if it failed
+	expires time.Time
+	err     error
+}
+
+// remoteFetch is a request in progress whose result is available once done is closed
+type remoteFetch struct {
+	done chan struct{}
+	keys *remoteKeys
+	err  error
+}
+
+// Timeout of the default client used to fetch keys
+const defaultFetchTimeout = 10 * time.Second
+
+// RemoteKeySetOption configures a RemoteKeySet
+type RemoteKeySetOption func(*RemoteKeySet)
+
+// WithAllowedKeyURLs allows tokens to point to the JWKS at any of urls using their key URL
+// Only exact matches are allowed
+func WithAllowedKeyURLs(urls ...string) RemoteKeySetOption {
+	return func(s *RemoteKeySet) {
+		for _, u := range urls {
+			s.allowed[u] = true
+		}
+	}
+}
+
+// WithHTTPClient sets the client used to fetch keys
+// By default a client with a timeout of 10 seconds is used, custom clients should have a timeout as well since callers wait for requests in progress
+func WithHTTPClient(client *http.Client) RemoteKeySetOption {
+	return func(s *RemoteKeySet) {
+		s.client = client
+	}
+}
+
+// WithCacheTTL sets how long keys are cached when the response does not specify a maximum age
+// The default is one hour
+func WithCacheTTL(ttl time.Duration) RemoteKeySetOption {
+	return func(s *RemoteKeySet) {
+		s.ttl = ttl
+	}
+}
+
+// WithRefreshInterval sets the minimum time between two requests to the same URL, even if tokens contain unknown key IDs
+// The default is one minute
+func WithRefreshInterval(interval time.Duration) RemoteKeySetOption {
+	return func(s *RemoteKeySet) {
+		s.interval = interval
+	}
+}
+
+// NewRemoteKeySet returns a new RemoteKeySet fetching keys from url which has to use HTTPS
+func NewRemoteKeySet(url string, opts ...RemoteKeySetOption) (*RemoteKeySet, error) {
+	s := &RemoteKeySet{
+		url:      url,
+		allowed:  make(map[string]bool),
+		client:   &http.Client{Timeout: defaultFetchTimeout},
+		ttl:      time.Hour,
+		interval: time.Minute,
+		now:      time.Now,
+		cache:    make(map[string]*remoteKeys),
+		inflight: make(map[string]*remoteFetch),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.allowed[url] = true
+	for u := range s.allowed {
+		if len(u) < 13 || u[:8] != "https://" {
+			return nil, errors.New("valid URL with HTTPS required")
+		}
+	}
+	if s.client == nil {
+		return nil, errors.New("client may not be nil")
+	}
+	if s.ttl < 0 || s.interval < 0 {
+		return nil, errors.New("cache TTL and refresh interval may not be negative")
+	}
+	return s, nil
+}
+
+// Keys returns the keys fetched from the key URL of the token or the configured URL if the token does not contain one
+func (s *RemoteKeySet) Keys(h Header) ([]crypto.PublicKey, error) {
+	url := s.url
+	if h.Jku != "" {
+		if !s.allowed[h.Jku] {
+			return nil, errors.New("key URL " + h.Jku + " is not allowed")
+		}
+		url = h.Jku
+	}
+
+	entry, err := s.load(url, h.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if h.Kid == "" {
+		if len(entry.all) == 0 {
+			return nil, errors.New("no keys available at " + url)
+		}
+		return entry.all, nil
+	}
+	key, ok := entry.keys[h.Kid]
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrUnknownKey, h.Kid)
+	}
+	return []crypto.PublicKey{key}, nil
+}
+
+// load returns the cached keys for url and fetches them first if they are missing, expired or don't contain kid
+// Only one request per URL is made at a time and concurrent callers wait for its result without holding the lock
+func (s *RemoteKeySet) load(url, kid string) (*remoteKeys, error) {
+	s.lock.Lock()
+	now := s.now()
+	entry := s.cache[url]
+	if !s.needsRefresh(entry, kid, now) {
+		s.lock.Unlock()
+		if entry.keys == nil {
+			return nil, entry.err
+		}
+		return entry, nil
+	}
+	f, waiting := s.inflight[url]
+	if !waiting {
+		f = &remoteFetch{done: make(chan struct{})}
+		s.inflight[url] = f
+	}
+	s.lock.Unlock()
+
+	if waiting {
+		<-f.done
+	} else {
+		s.refresh(url, now, f)
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.keys, nil
+}
+
+// needsRefresh reports whether the keys for a token with key ID kid have to be fetched again
+// Keys that have never been fetched successfully, have expired or don't contain kid are only fetched again after the refresh interval
+func (s *RemoteKeySet) needsRefresh(entry *remoteKeys, kid string, now time.Time) bool {
+	if entry == nil {
+		return true
+	}
+	if !s.mayRefresh(entry, now) {
+		return false
+	}
+	if entry.keys == nil || now.After(entry.expires) {
+		return true
+	}
+	_, ok := entry.keys[kid]
+	return kid != "" && !ok
+}
+
+func (s *RemoteKeySet) mayRefresh(entry *remoteKeys, now time.Time) bool {
+	return !now.Before(entry.fetched.Add(s.interval))
+}
+
+// refresh fetches the keys from url, stores them in the cache and reports the result to all callers waiting for f
+// A failed request is recorded as well so it is not repeated before the refresh interval has passed
+// It only fails if no keys have been fetched from url before
+func (s *RemoteKeySet) refresh(url string, now time.Time, f *remoteFetch) {
+	keys, ttl, err := s.fetch(url)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err != nil {
+		// Keys fetched before are still used until the next refresh, including by the callers waiting for this one
+		var entry remoteKeys
+		if cached := s.cache[url]; cached != nil {
+			entry = *cached
+		}
+		entry.fetched = now
+		entry.err = err
+		s.cache[url] = &entry
+		if entry.keys != nil {
+			f.keys = &entry
+		} else {
+			f.err = err
+		}
+	} else {
+		keys.fetched = now
+		keys.expires = now.Add(ttl)
+		s.cache[url] = keys
+		f.keys = keys
+	}
+	delete(s.inflight, url)
+	close(f.done)
+}
+
+func (s *RemoteKeySet) fetch(url string) (keys *remoteKeys, ttl time.Duration, err error) {
+	resp, err := s.client.Get(url)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("fetching keys from %s failed with status %d", url, resp.StatusCode)
+		return
+	}
+	var set JWKS
+	err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&set)
+	if err != nil {
+		return
+	}
+	keys = &remoteKeys{keys: make(map[string]crypto.PublicKey)}
+	for _, k := range set.Keys {
+		// Key sets may contain keys of other types that are not supported and can therefore be skipped
+		key, err := k.PublicKey()
+		if err != nil || checkPublicKey(key) != nil || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		keys.all = append(keys.all, key)
+		if k.Kid != "" {
+			keys.keys[k.Kid] = key
+		}
+	}
+	ttl = cacheTTL(resp.Header.Get("Cache-Control"), s.ttl)
+	return
+}
+
+// cacheTTL returns the maximum age allowed by a Cache-Control header or def if it does not contain one
+func cacheTTL(cacheControl string, def time.Duration) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-cache" || directive == "no-store":
+			return 0
+		case strings.HasPrefix(directive, "max-age="):
+			age, err := strconv.Atoi(strings.Trim(directive[8:], `"`))
+			if err == nil && age >= 0 {
+				return time.Duration(age) * time.Second
+			}
+		}
+	}
+	return def
+}
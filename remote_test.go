@@ -0,0 +1,323 @@
+package jwt
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+type testJWKSServer struct {
+	*httptest.Server
+	lock         sync.Mutex
+	set          JWKS
+	cacheControl string
+	status       int
+	requests     int
+}
+
+func newTestJWKSServer(t *testing.T) *testJWKSServer {
+	s := &testJWKSServer{}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		s.requests++
+		if s.cacheControl != "" {
+			w.Header().Set("Cache-Control", s.cacheControl)
+		}
+		if s.status != 0 {
+			w.WriteHeader(s.status)
+			return
+		}
+		json.NewEncoder(w).Encode(s.set)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testJWKSServer) addKey(t *testing.T, kid string) ed25519.PublicKey {
+	public, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	k, err := NewJWK(public)
+	if err != nil {
+		t.Fatalf("Failed to create JWK: %s", err.Error())
+	}
+	k.Kid = kid
+	s.lock.Lock()
+	s.set.Keys = append(s.set.Keys, k)
+	s.lock.Unlock()
+	return public
+}
+
+func (s *testJWKSServer) count() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.requests
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	srv := newTestJWKSServer(t)
+	other := newTestJWKSServer(t)
+	forbidden := newTestJWKSServer(t)
+	key1 := srv.addKey(t, "key1")
+	otherKey := other.addKey(t, "other")
+	forbidden.addKey(t, "forbidden")
+	srv.set.Keys = append(srv.set.Keys, JWK{Kty: "RSA", Kid: "rsa"})
+
+	now := time.Now()
+	ks, err := NewRemoteKeySet(srv.URL, WithHTTPClient(srv.Client()), WithAllowedKeyURLs(other.URL), WithCacheTTL(time.Hour), WithRefreshInterval(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to create remote key set: %s", err.Error())
+	}
+	ks.now = func() time.Time { return now }
+
+	keys, err := ks.Keys(Header{Kid: "key1"})
+	if err != nil || len(keys) != 1 || !key1.Equal(keys[0]) {
+		t.Fatalf("RemoteKeySet.Keys() = %v, %v, want key1", keys, err)
+	}
+	if _, err := ks.Keys(Header{Kid: "rsa"}); err == nil {
+		t.Error("RemoteKeySet.Keys() returned unsupported key")
+	}
+	keys, err = ks.Keys(Header{})
+	if err != nil || len(keys) != 1 {
+		t.Errorf("RemoteKeySet.Keys() without key ID = %v, %v, want all keys", keys, err)
+	}
+	if n := srv.count(); n != 1 {
+		t.Errorf("Keys were fetched %d times, want 1", n)
+	}
+
+	// Unknown key IDs trigger a refresh but only once per refresh interval
+	key2 := srv.addKey(t, "key2")
+	if _, err := ks.Keys(Header{Kid: "key2"}); err == nil {
+		t.Error("RemoteKeySet.Keys() refreshed keys before refresh interval passed")
+	}
+	now = now.Add(time.Minute)
+	keys, err = ks.Keys(Header{Kid: "key2"})
+	if err != nil || !key2.Equal(keys[0]) {
+		t.Errorf("RemoteKeySet.Keys() = %v, %v, want key2", keys, err)
+	}
+	if _, err := ks.Keys(Header{Kid: "key3"}); err == nil {
+		t.Error("RemoteKeySet.Keys() accepted unknown key ID")
+	}
+	if n := srv.count(); n != 2 {
+		t.Errorf("Keys were fetched %d times, want 2", n)
+	}
+
+	// Cached keys expire after the maximum age set by the server
+	srv.lock.Lock()
+	srv.cacheControl = "public, max-age=120"
+	srv.lock.Unlock()
+	now = now.Add(2 * time.Hour)
+	if _, err := ks.Keys(Header{Kid: "key1"}); err != nil {
+		t.Errorf("RemoteKeySet.Keys() failed after cache expired: %s", err.Error())
+	}
+	now = now.Add(time.Minute)
+	ks.Keys(Header{Kid: "key1"})
+	now = now.Add(2 * time.Minute)
+	ks.Keys(Header{Kid: "key1"})
+	if n := srv.count(); n != 4 {
+		t.Errorf("Keys were fetched %d times, want 4", n)
+	}
+
+	// Key URLs are only used when they are allowed
+	keys, err = ks.Keys(Header{Kid: "other", Jku: other.URL})
+	if err != nil || !otherKey.Equal(keys[0]) {
+		t.Errorf("RemoteKeySet.Keys() = %v, %v, want key from allowed key URL", keys, err)
+	}
+	if _, err := ks.Keys(Header{Kid: "forbidden", Jku: forbidden.URL}); err == nil {
+		t.Error("RemoteKeySet.Keys() accepted key URL that is not allowed")
+	}
+	if n := forbidden.count(); n != 0 {
+		t.Errorf("Key URL that is not allowed was requested %d times", n)
+	}
+}
+
+func TestRemoteKeySet_FailedRequest(t *testing.T) {
+	srv := newTestJWKSServer(t)
+	key := srv.addKey(t, "key")
+	srv.status = http.StatusInternalServerError
+
+	now := time.Now()
+	ks, err := NewRemoteKeySet(srv.URL, WithHTTPClient(srv.Client()), WithRefreshInterval(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to create remote key set: %s", err.Error())
+	}
+	ks.now = func() time.Time { return now }
+
+	// Failed requests are not repeated before the refresh interval has passed, even if no keys have been fetched, yet
+	for i := 0; i < 3; i++ {
+		if _, err := ks.Keys(Header{Kid: "key"}); err == nil {
+			t.Error("RemoteKeySet.Keys() succeeded although request failed")
+		}
+	}
+	if n := srv.count(); n != 1 {
+		t.Errorf("Keys were fetched %d times, want 1", n)
+	}
+
+	srv.lock.Lock()
+	srv.status = 0
+	srv.lock.Unlock()
+	now = now.Add(time.Minute)
+	keys, err := ks.Keys(Header{Kid: "key"})
+	if err != nil || !key.Equal(keys[0]) {
+		t.Errorf("RemoteKeySet.Keys() = %v, %v, want key", keys, err)
+	}
+	if n := srv.count(); n != 2 {
+		t.Errorf("Keys were fetched %d times, want 2", n)
+	}
+}
+
+func TestRemoteKeySet_FailedRefresh(t *testing.T) {
+	srv := newTestJWKSServer(t)
+	key := srv.addKey(t, "key")
+
+	now := time.Now()
+	ks, err := NewRemoteKeySet(srv.URL, WithHTTPClient(srv.Client()), WithCacheTTL(time.Hour), WithRefreshInterval(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to create remote key set: %s", err.Error())
+	}
+	ks.now = func() time.Time { return now }
+	if _, err := ks.Keys(Header{Kid: "key"}); err != nil {
+		t.Fatalf("RemoteKeySet.Keys() failed: %s", err.Error())
+	}
+
+	// Keys fetched before are used when refreshing expired keys fails
+	srv.lock.Lock()
+	srv.status = http.StatusInternalServerError
+	srv.lock.Unlock()
+	now = now.Add(2 * time.Hour)
+	for i := 0; i < 2; i++ {
+		keys, err := ks.Keys(Header{Kid: "key"})
+		if err != nil || !key.Equal(keys[0]) {
+			t.Errorf("RemoteKeySet.Keys() = %v, %v, want previously fetched key", keys, err)
+		}
+	}
+	if n := srv.count(); n != 2 {
+		t.Errorf("Keys were fetched %d times, want 2", n)
+	}
+}
+
+func TestRemoteKeySet_Concurrent(t *testing.T) {
+	srv := newTestJWKSServer(t)
+	other := newTestJWKSServer(t)
+	srv.addKey(t, "key")
+	otherKey := other.addKey(t, "other")
+	release := make(chan struct{})
+	handler := srv.Config.Handler
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		handler.ServeHTTP(w, r)
+	})
+
+	ks, err := NewRemoteKeySet(srv.URL, WithHTTPClient(srv.Client()), WithAllowedKeyURLs(other.URL))
+	if err != nil {
+		t.Fatalf("Failed to create remote key set: %s", err.Error())
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := ks.Keys(Header{Kid: "key"})
+			errs <- err
+		}()
+	}
+
+	// Keys from other URLs are available while a request is in progress
+	keys, err := ks.Keys(Header{Kid: "other", Jku: other.URL})
+	if err != nil || !otherKey.Equal(keys[0]) {
+		t.Errorf("RemoteKeySet.Keys() = %v, %v, want key from other URL", keys, err)
+	}
+
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("RemoteKeySet.Keys() failed: %s", err.Error())
+		}
+	}
+	if n := srv.count(); n != 1 {
+		t.Errorf("Keys were fetched %d times concurrently, want 1", n)
+	}
+}
+
+func TestRemoteKeySet_Verify(t *testing.T) {
+	srv := newTestJWKSServer(t)
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSignerWithKeyIDAndKeyURL(key, "key", srv.URL)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	srv.Config.Handler, err = JWKSHandler(s)
+	if err != nil {
+		t.Fatalf("Failed to create handler: %s", err.Error())
+	}
+	ks, err := NewRemoteKeySet(srv.URL, WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatalf("Failed to create remote key set: %s", err.Error())
+	}
+	v, err := NewVerifierWithKeySet(ks)
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %s", err.Error())
+	}
+	enc, err := s.Sign(RegisteredClaims{Subject: "test"})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+	if _, err := v.Verify(string(enc)); err != nil {
+		t.Errorf("Verifier.Verify() failed using remote key set: %s", err.Error())
+	}
+}
+
+func TestNewRemoteKeySet(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		opts []RemoteKeySetOption
+	}{
+		{"NotHTTPS", "http://example.com/keys", nil},
+		{"AllowedNotHTTPS", "https://example.com/keys", []RemoteKeySetOption{WithAllowedKeyURLs("http://example.com/keys")}},
+		{"NilClient", "https://example.com/keys", []RemoteKeySetOption{WithHTTPClient(nil)}},
+		{"NegativeTTL", "https://example.com/keys", []RemoteKeySetOption{WithCacheTTL(-time.Second)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRemoteKeySet(tt.url, tt.opts...); err == nil {
+				t.Error("NewRemoteKeySet() accepted invalid configuration")
+			}
+		})
+	}
+}
+
+func Test_cacheTTL(t *testing.T) {
+	tests := []struct {
+		name         string
+		cacheControl string
+		want         time.Duration
+	}{
+		{"Empty", "", time.Hour},
+		{"MaxAge", "max-age=300", 5 * time.Minute},
+		{"MaxAgeWithOtherDirectives", "public, Max-Age=60, must-revalidate", time.Minute},
+		{"NoCache", "no-cache", 0},
+		{"NoStore", "no-store", 0},
+		{"InvalidMaxAge", "max-age=soon", time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cacheTTL(tt.cacheControl, time.Hour); got != tt.want {
+				t.Errorf("cacheTTL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
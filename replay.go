@@ -0,0 +1,120 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ReplayStore records the IDs of tokens that have been used to reject them when they are used again
+// As token IDs are only unique per issuer, the Verifier passes the issuer and token ID separated by a NUL byte as id
+type ReplayStore interface {
+	// Seen records id as used until expiry and reports whether it has already been recorded and not expired, yet
+	// Checking and recording has to happen atomically, so a token used concurrently is only accepted once
+	// Stores that cannot record id have to return an error, so the token is rejected instead of becoming usable again
+	Seen(id string, expiry time.Time) (bool, error)
+}
+
+// MemoryReplayStore is a ReplayStore holding up to a fixed number of token IDs in memory
+// IDs are removed once they have expired and IDs that have not expired, yet, are never evicted, so tokens are rejected with ErrReplayStoreFull while the store is full
+// The size should therefore exceed the number of tokens that are valid at once
+// A MemoryReplayStore may be used by multiple goroutines at once
+type MemoryReplayStore struct {
+	lock  sync.Mutex
+	clock Clock
+	size  int
+	ids   map[string]time.Time // Expiry of each ID
+}
+
+// NewMemoryReplayStore returns an empty MemoryReplayStore holding up to size IDs
+func NewMemoryReplayStore(size int) (*MemoryReplayStore, error) {
+	return NewMemoryReplayStoreWithClock(size, systemClock{})
+}
+
+// NewMemoryReplayStoreWithClock returns an empty MemoryReplayStore like NewMemoryReplayStore but uses clock to determine when IDs have expired
+func NewMemoryReplayStoreWithClock(size int, clock Clock) (*MemoryReplayStore, error) {
+	if size <= 0 {
+		return nil, errors.New("size has to be positive")
+	}
+	if clock == nil {
+		return nil, errors.New("clock may not be nil")
+	}
+	return &MemoryReplayStore{clock: clock, size: size, ids: make(map[string]time.Time)}, nil
+}
+
+// Seen records id as used until expiry and reports whether it has already been recorded and not expired, yet
+// It returns ErrReplayStoreFull if the store is full and none of the IDs in it have expired
+func (s *MemoryReplayStore) Seen(id string, expiry time.Time) (bool, error) {
+	now := s.clock.Now()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if e, ok := s.ids[id]; ok {
+		if now.Before(e) {
+			return true, nil
+		}
+		delete(s.ids, id)
+	}
+	if len(s.ids) >= s.size {
+		s.removeExpired(now)
+	}
+	if len(s.ids) >= s.size {
+		return false, ErrReplayStoreFull
+	}
+	s.ids[id] = expiry
+	return false, nil
+}
+
+// Len returns the number of IDs in the store including those that have expired but were not removed, yet
+func (s *MemoryReplayStore) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.ids)
+}
+
+// removeExpired removes all IDs that have expired at now
+func (s *MemoryReplayStore) removeExpired(now time.Time) {
+	for id, expiry := range s.ids {
+		if !now.Before(expiry) {
+			delete(s.ids, id)
+		}
+	}
+}
+
+// NewTokenID returns a random token ID suitable for the jti claim
+// It contains 128 random bits encoded as base64url
+func NewTokenID() (string, error) {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(id), nil
+}
+
+// withTokenID returns content as map with a random jti claim added unless it already contains one
+func withTokenID(content interface{}) (interface{}, error) {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	var claims map[string]json.RawMessage
+	err = json.Unmarshal(data, &claims)
+	if err != nil {
+		return nil, err
+	}
+	if claims == nil {
+		claims = make(map[string]json.RawMessage)
+	}
+	if _, ok := claims["jti"]; ok {
+		return claims, nil
+	}
+	id, err := NewTokenID()
+	if err != nil {
+		return nil, err
+	}
+	claims["jti"], _ = json.Marshal(id) // Error is safe to ignore as encoding a string can't fail
+	return claims, nil
+}
@@ -0,0 +1,174 @@
+package jwt
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestMemoryReplayStore_Seen(t *testing.T) {
+	clock := &testClock{time.Unix(1000, 0)}
+	s, err := NewMemoryReplayStoreWithClock(2, clock)
+	if err != nil {
+		t.Fatalf("Failed to create replay store: %s", err.Error())
+	}
+	seen := func(id string, expiry time.Time, want bool) {
+		t.Helper()
+		got, err := s.Seen(id, expiry)
+		if err != nil || got != want {
+			t.Errorf("MemoryReplayStore.Seen(%s) = %v, %v, want %v", id, got, err, want)
+		}
+	}
+
+	seen("a", clock.now.Add(time.Minute), false)
+	seen("a", clock.now.Add(time.Minute), true)
+	seen("b", clock.now.Add(time.Hour), false)
+
+	// Expired IDs are removed before IDs that have not expired, yet
+	clock.now = clock.now.Add(2 * time.Minute)
+	seen("c", clock.now.Add(time.Hour), false)
+	seen("b", clock.now.Add(time.Hour), true)
+	if s.Len() != 2 {
+		t.Errorf("MemoryReplayStore.Len() = %d, want 2", s.Len())
+	}
+
+	// IDs that have not expired, yet, are never evicted
+	if _, err := s.Seen("d", clock.now.Add(time.Hour)); !errors.Is(err, ErrReplayStoreFull) {
+		t.Errorf("MemoryReplayStore.Seen() error = %v, want %v", err, ErrReplayStoreFull)
+	}
+	seen("b", clock.now.Add(time.Hour), true)
+	seen("c", clock.now.Add(time.Hour), true)
+	clock.now = clock.now.Add(2 * time.Hour)
+	seen("d", clock.now.Add(time.Hour), false)
+
+	if _, err := NewMemoryReplayStore(0); err == nil {
+		t.Error("NewMemoryReplayStore() accepted size 0")
+	}
+	if _, err := NewMemoryReplayStoreWithClock(1, nil); err == nil {
+		t.Error("NewMemoryReplayStoreWithClock() accepted nil clock")
+	}
+}
+
+func TestMemoryReplayStore_Concurrent(t *testing.T) {
+	s, err := NewMemoryReplayStore(16)
+	if err != nil {
+		t.Fatalf("Failed to create replay store: %s", err.Error())
+	}
+	var accepted int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if seen, _ := s.Seen("id", time.Now().Add(time.Minute)); !seen {
+				atomic.AddInt32(&accepted, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if accepted != 1 {
+		t.Errorf("MemoryReplayStore.Seen() accepted the same ID %d times", accepted)
+	}
+}
+
+func TestWithReplayStore(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	store, err := NewMemoryReplayStore(16)
+	if err != nil {
+		t.Fatalf("Failed to create replay store: %s", err.Error())
+	}
+	v, err := NewVerifier(public, WithReplayStore(store), WithIssuer("issuer"))
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %s", err.Error())
+	}
+	withMaxAge, err := NewVerifier(public, WithReplayStore(store), WithMaxAge(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %s", err.Error())
+	}
+	now := time.Now()
+	exp := NewNumericDate(now.Add(time.Minute))
+	tests := []struct {
+		name    string
+		v       *Verifier
+		claims  RegisteredClaims
+		wantErr error
+	}{
+		{"Valid", v, RegisteredClaims{Issuer: "issuer", ID: "1", ExpiresAt: exp}, nil},
+		{"Replayed", v, RegisteredClaims{Issuer: "issuer", ID: "1", ExpiresAt: exp}, ErrReplayed},
+		{"MissingID", v, RegisteredClaims{Issuer: "issuer", ExpiresAt: exp}, ErrClaimMissing},
+		{"MissingExpiry", v, RegisteredClaims{Issuer: "issuer", ID: "2"}, ErrClaimMissing},
+		// Tokens failing other rules must not be recorded
+		{"WrongIssuer", v, RegisteredClaims{Issuer: "other", ID: "3", ExpiresAt: exp}, ErrClaimInvalid},
+		{"NotRecorded", v, RegisteredClaims{Issuer: "issuer", ID: "3", ExpiresAt: exp}, nil},
+		{"MaxAge", withMaxAge, RegisteredClaims{ID: "4", IssuedAt: NewNumericDate(now)}, nil},
+		{"MaxAgeReplayed", withMaxAge, RegisteredClaims{ID: "4", IssuedAt: NewNumericDate(now)}, ErrReplayed},
+		// IDs are only unique per issuer
+		{"OtherIssuer", withMaxAge, RegisteredClaims{Issuer: "other", ID: "4", IssuedAt: NewNumericDate(now)}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc, err := s.Sign(tt.claims)
+			if err != nil {
+				t.Fatalf("Failed to sign token: %s", err.Error())
+			}
+			if _, err := tt.v.Verify(string(enc)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verifier.Verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSigner_WithRandomID(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	r := s.WithRandomID()
+	if s.randomID {
+		t.Error("WithRandomID() modified original signer")
+	}
+	ids := make(map[string]bool)
+	for _, content := range []interface{}{
+		testClaims{RegisteredClaims: RegisteredClaims{Subject: "1234"}, Name: "John Doe"},
+		map[string]interface{}{"sub": "1234"},
+		map[string]interface{}{},
+	} {
+		enc, err := r.Sign(content)
+		if err != nil {
+			t.Fatalf("Failed to sign token: %s", err.Error())
+		}
+		tok, err := DecodeInto[testClaims](string(enc))
+		if err != nil {
+			t.Fatalf("Failed to decode token: %s", err.Error())
+		}
+		if len(tok.Claims.ID) != 22 || ids[tok.Claims.ID] {
+			t.Errorf("Signer.Sign() token ID = %s, want new random ID", tok.Claims.ID)
+		}
+		ids[tok.Claims.ID] = true
+	}
+
+	// Existing token IDs are kept
+	enc, err := r.Sign(RegisteredClaims{ID: "id"})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+	tok, err := DecodeInto[RegisteredClaims](string(enc))
+	if err != nil || tok.Claims.ID != "id" {
+		t.Errorf("Signer.Sign() token ID = %s, %v, want id", tok.Claims.ID, err)
+	}
+}
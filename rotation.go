@@ -0,0 +1,139 @@
+package jwt
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// KeyManager holds the active signing key and previous keys that are still valid for validation
+// Each key is assigned its JWK thumbprint as key ID and previous keys are retired once the grace period after their rotation has passed
+// A KeyManager is a KeySet containing all keys that are not retired and may be used by multiple goroutines at once
+type KeyManager struct {
+	lock     sync.RWMutex
+	clock    Clock
+	grace    time.Duration
+	active   *Signer
+	previous []rotatedKey
+}
+
+type rotatedKey struct {
+	kid     string
+	key     crypto.PublicKey
+	retires time.Time
+}
+
+// NewKeyManager returns a new KeyManager using key as active signing key that keeps previous keys for gracePeriod after rotation
+func NewKeyManager(key crypto.PrivateKey, gracePeriod time.Duration) (*KeyManager, error) {
+	return NewKeyManagerWithClock(key, gracePeriod, systemClock{})
+}
+
+// NewKeyManagerWithClock returns a new KeyManager like NewKeyManager but uses clock to determine when keys are retired
+func NewKeyManagerWithClock(key crypto.PrivateKey, gracePeriod time.Duration, clock Clock) (*KeyManager, error) {
+	if gracePeriod < 0 {
+		return nil, errors.New("grace period may not be negative")
+	}
+	if clock == nil {
+		return nil, errors.New("clock may not be nil")
+	}
+	s, err := newThumbprintSigner(key)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyManager{clock: clock, grace: gracePeriod, active: s}, nil
+}
+
+// newThumbprintSigner returns a Signer using the JWK thumbprint of key as key ID
+func newThumbprintSigner(key crypto.PrivateKey) (*Signer, error) {
+	k, err := NewPrivateJWK(key)
+	if err != nil {
+		return nil, err
+	}
+	kid, _ := k.Thumbprint() // Error is safe to ignore as k is always a valid key
+	return NewSignerWithKeyID(key, kid)
+}
+
+// Rotate makes key the active signing key
+// The previous key remains valid for validation until the grace period has passed
+func (m *KeyManager) Rotate(key crypto.PrivateKey) error {
+	s, err := newThumbprintSigner(key)
+	if err != nil {
+		return err
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if s.KeyID() == m.active.KeyID() {
+		return errors.New("key is already the active key")
+	}
+	m.previous = append(m.previous, rotatedKey{m.active.KeyID(), m.active.Public(), m.clock.Now().Add(m.grace)})
+	m.active = s
+	return nil
+}
+
+// Signer returns the Signer using the active signing key
+func (m *KeyManager) Signer() *Signer {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.active
+}
+
+// Sign creates a new JWT containing content and returns it encoded and signed using the active signing key
+func (m *KeyManager) Sign(content interface{}) ([]byte, error) {
+	return m.Signer().Sign(content)
+}
+
+// Keys returns the key with the key ID of the token if it is the active key or a previous key that has not been retired
+func (m *KeyManager) Keys(h Header) ([]crypto.PublicKey, error) {
+	if h.Kid == "" {
+		return nil, errors.New("token does not contain a key ID")
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.retire()
+	if h.Kid == m.active.KeyID() {
+		return []crypto.PublicKey{m.active.Public()}, nil
+	}
+	for _, k := range m.previous {
+		if k.kid == h.Kid {
+			return []crypto.PublicKey{k.key}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %s", ErrUnknownKey, h.Kid)
+}
+
+// JWKS returns the public keys of the active key and all previous keys that have not been retired
+func (m *KeyManager) JWKS() JWKS {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.retire()
+	set := JWKS{Keys: make([]JWK, 0, len(m.previous)+1)}
+	k, _ := NewJWK(m.active.Public()) // Error is safe to ignore as signers always contain a valid key
+	k.Kid = m.active.KeyID()
+	set.Keys = append(set.Keys, k)
+	for i := len(m.previous) - 1; i >= 0; i-- {
+		k, _ := NewJWK(m.previous[i].key) // Error is safe to ignore as only keys of signers are kept
+		k.Kid = m.previous[i].kid
+		set.Keys = append(set.Keys, k)
+	}
+	return set
+}
+
+// ServeHTTP serves the current JWKS of the key manager
+func (m *KeyManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data, _ := json.Marshal(m.JWKS()) // Error is safe to ignore as encoding a struct containing only strings can't fail
+	jwksHandler(data).ServeHTTP(w, r)
+}
+
+// retire removes all previous keys whose grace period has passed and has to be called while holding the lock
+func (m *KeyManager) retire() {
+	now := m.clock.Now()
+	i := 0
+	for i < len(m.previous) && !now.Before(m.previous[i].retires) {
+		i++
+	}
+	m.previous = m.previous[i:]
+}
@@ -0,0 +1,125 @@
+package jwt
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func TestKeyManager(t *testing.T) {
+	generate := func() ed25519.PrivateKey {
+		_, key, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+		}
+		return key
+	}
+	clock := &testClock{time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	key1 := generate()
+	m, err := NewKeyManagerWithClock(key1, 7*24*time.Hour, clock)
+	if err != nil {
+		t.Fatalf("Failed to create key manager: %s", err.Error())
+	}
+	k, _ := NewJWK(key1.Public().(ed25519.PublicKey))
+	thumbprint, _ := k.Thumbprint()
+	if m.Signer().KeyID() != thumbprint {
+		t.Errorf("Key ID %s is not the thumbprint %s of the key", m.Signer().KeyID(), thumbprint)
+	}
+
+	token1, err := m.Sign(map[string]interface{}{"test": 1})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+	verify := func(token []byte) error {
+		dec, err := Decode(string(token))
+		if err != nil {
+			t.Fatalf("Failed to decode token: %s", err.Error())
+		}
+		return dec.VerifyWithKeySet(m)
+	}
+	if err := verify(token1); err != nil {
+		t.Errorf("Failed to verify token signed with active key: %s", err.Error())
+	}
+
+	// Rotate monthly and check that previous keys remain valid during the grace period
+	clock.now = clock.now.AddDate(0, 1, 0)
+	if err := m.Rotate(key1); err == nil {
+		t.Error("KeyManager.Rotate() accepted the active key")
+	}
+	if err := m.Rotate(ed25519.PrivateKey("test")); err == nil {
+		t.Error("KeyManager.Rotate() accepted invalid private key")
+	}
+	if err := m.Rotate(generate()); err != nil {
+		t.Fatalf("Failed to rotate key: %s", err.Error())
+	}
+	token2, err := m.Sign(map[string]interface{}{"test": 2})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+	if err := verify(token1); err != nil {
+		t.Errorf("Failed to verify token signed with previous key during grace period: %s", err.Error())
+	}
+	if err := verify(token2); err != nil {
+		t.Errorf("Failed to verify token signed with active key: %s", err.Error())
+	}
+	if set := m.JWKS(); len(set.Keys) != 2 || set.Keys[0].Kid != m.Signer().KeyID() || set.Keys[1].Kid != thumbprint {
+		t.Errorf("KeyManager.JWKS() = %+v, want active and previous key", set)
+	}
+
+	clock.now = clock.now.Add(7*24*time.Hour - time.Second)
+	if err := verify(token1); err != nil {
+		t.Errorf("Failed to verify token signed with previous key before grace period passed: %s", err.Error())
+	}
+	clock.now = clock.now.Add(time.Second)
+	if err := verify(token1); err == nil {
+		t.Error("Token signed with retired key was verified")
+	}
+	if err := verify(token2); err != nil {
+		t.Errorf("Failed to verify token signed with active key: %s", err.Error())
+	}
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	var set JWKS
+	if err := json.Unmarshal(rec.Body.Bytes(), &set); err != nil {
+		t.Fatalf("Failed to unmarshal JWKS: %s", err.Error())
+	}
+	if len(set.Keys) != 1 || set.Keys[0].Kid != m.Signer().KeyID() {
+		t.Errorf("KeyManager.ServeHTTP() served %+v, want only the active key", set)
+	}
+
+	if _, err := m.Keys(Header{}); err == nil {
+		t.Error("KeyManager.Keys() accepted token without key ID")
+	}
+}
+
+func TestNewKeyManager(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	if _, err := NewKeyManager(key, time.Hour); err != nil {
+		t.Errorf("NewKeyManager() failed: %s", err.Error())
+	}
+	if _, err := NewKeyManager(key, -time.Hour); err == nil {
+		t.Error("NewKeyManager() accepted negative grace period")
+	}
+	if _, err := NewKeyManager(ed25519.PrivateKey("test"), time.Hour); err == nil {
+		t.Error("NewKeyManager() accepted invalid private key")
+	}
+	if _, err := NewKeyManagerWithClock(key, time.Hour, nil); err == nil {
+		t.Error("NewKeyManagerWithClock() accepted nil clock")
+	}
+}
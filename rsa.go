@@ -0,0 +1,62 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+)
+
+// SigningMethodRS256 implements RSASSA-PKCS1-v1_5 using SHA-256 as defined in RFC 7518
+var SigningMethodRS256 SigningMethod = signingMethodRSA{"RS256", crypto.SHA256, false}
+
+// SigningMethodPS256 implements RSASSA-PSS using SHA-256 as defined in RFC 7518
+var SigningMethodPS256 SigningMethod = signingMethodRSA{"PS256", crypto.SHA256, true}
+
+// Keys shorter than 2048 bits are rejected as required by RFC 7518
+const minRSAKeySize = 2048
+
+type signingMethodRSA struct {
+	alg  string
+	hash crypto.Hash
+	pss  bool
+}
+
+func (m signingMethodRSA) Alg() string {
+	return m.alg
+}
+
+func (m signingMethodRSA) digest(data []byte) []byte {
+	h := m.hash.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func (m signingMethodRSA) Sign(data []byte, key crypto.PrivateKey) ([]byte, error) {
+	k, ok := key.(*rsa.PrivateKey)
+	if !ok || k == nil || k.N.BitLen() < minRSAKeySize {
+		return nil, errors.New("key is not a valid " + m.alg + " private key")
+	}
+	if m.pss {
+		return rsa.SignPSS(rand.Reader, k, m.hash, m.digest(data), &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	}
+	return rsa.SignPKCS1v15(rand.Reader, k, m.hash, m.digest(data))
+}
+
+func (m signingMethodRSA) Verify(data, signature []byte, key crypto.PublicKey) error {
+	k, ok := key.(*rsa.PublicKey)
+	if !ok || k == nil || k.N.BitLen() < minRSAKeySize {
+		return fmt.Errorf("%w for algorithm %s", ErrInvalidKey, m.alg)
+	}
+	var err error
+	if m.pss {
+		err = rsa.VerifyPSS(k, m.hash, m.digest(data), signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	} else {
+		err = rsa.VerifyPKCS1v15(k, m.hash, m.digest(data), signature)
+	}
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
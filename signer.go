@@ -0,0 +1,134 @@
+package jwt
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+)
+
+// Signer encodes and signs tokens using a private key and optionally inserts a key ID and key URL into their header
+// The signing method is determined by the type of the key: EdDSA for ed25519.PrivateKey and ed448.PrivateKey, ES256 or ES384 for *ecdsa.PrivateKey depending on the curve, RS256 for *rsa.PrivateKey and HS256 for HMACKey
+// A Signer is immutable and may therefore be used by multiple goroutines at once
+type Signer struct {
+	key    crypto.PrivateKey
+	method SigningMethod
+	kid    string
+	jku    string
+	// Whether a random token ID is added to all tokens that don't contain one
+	randomID bool
+}
+
+// NewSigner returns a new Signer using key
+func NewSigner(key crypto.PrivateKey) (*Signer, error) {
+	err := checkPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	method, err := methodForKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{key: copyPrivateKey(key), method: method}, nil
+}
+
+// NewSignerWithKeyID returns a new Signer using key that inserts key ID into the header of all tokens
+func NewSignerWithKeyID(key crypto.PrivateKey, keyID string) (*Signer, error) {
+	if keyID == "" {
+		return nil, errors.New("empty key IDs are not supported")
+	}
+	s, err := NewSigner(key)
+	if err != nil {
+		return nil, err
+	}
+	s.kid = keyID
+	return s, nil
+}
+
+// NewSignerWithKeyIDAndKeyURL returns a new Signer using key that inserts key ID and key URL into the header of all tokens
+func NewSignerWithKeyIDAndKeyURL(key crypto.PrivateKey, keyID, keyURL string) (*Signer, error) {
+	if keyID == "" {
+		return nil, errors.New("empty key IDs are not supported")
+	}
+	if len(keyURL) < 13 || keyURL[:8] != "https://" {
+		return nil, errors.New("valid URL with HTTPS required")
+	}
+	s, err := NewSigner(key)
+	if err != nil {
+		return nil, err
+	}
+	s.kid = keyID
+	s.jku = keyURL
+	return s, nil
+}
+
+// WithAlgorithm returns a copy of s that signs tokens using the signing method registered for alg, e.g. PS256 for RSA keys
+// An error is returned if the method cannot sign using the key of s
+func (s *Signer) WithAlgorithm(alg string) (*Signer, error) {
+	method, ok := GetSigningMethod(alg)
+	if !ok {
+		return nil, &AlgorithmError{Alg: alg, Unsupported: true}
+	}
+	_, err := method.Sign(nil, s.key)
+	if err != nil {
+		return nil, err
+	}
+	c := *s
+	c.method = method
+	return &c, nil
+}
+
+// WithRandomID returns a copy of s that adds a random token ID created by NewTokenID as jti claim to all tokens signed using Sign unless their content already contains one
+// Content is converted to a map for this purpose, so the order of claims in the token may differ from the order of fields in a struct
+func (s *Signer) WithRandomID() *Signer {
+	c := *s
+	c.randomID = true
+	return &c
+}
+
+// Algorithm returns the algorithm used by s as inserted into the alg header
+func (s *Signer) Algorithm() string {
+	return s.method.Alg()
+}
+
+// KeyID returns the key ID inserted into the header of tokens signed by s
+func (s *Signer) KeyID() string {
+	return s.kid
+}
+
+// KeyURL returns the key URL inserted into the header of tokens signed by s
+func (s *Signer) KeyURL() string {
+	return s.jku
+}
+
+// Public returns the public key corresponding to the private key used by s
+func (s *Signer) Public() crypto.PublicKey {
+	return publicKeyFor(s.key)
+}
+
+// Sign creates a new JWT containing content and returns it encoded and signed
+// Content has to be either a struct or a map with string keys
+func (s *Signer) Sign(content interface{}) ([]byte, error) {
+	t, err := New(content)
+	if err != nil {
+		return nil, err
+	}
+	if s.randomID {
+		t.Content, err = withTokenID(t.Content)
+		if err != nil {
+			return nil, err
+		}
+	}
+	t.Header.Kid = s.kid
+	t.Header.Jku = s.jku
+	return s.Encode(&t)
+}
+
+// Encode encodes t and signs it
+// The algorithm in the header is set to the one used by s, all other fields of the header are used as is
+func (s *Signer) Encode(t *JWT) ([]byte, error) {
+	content, err := json.Marshal(t.Content)
+	if err != nil {
+		return nil, err
+	}
+	return s.encodeJWS(t.Header, content)
+}
@@ -0,0 +1,119 @@
+package jwt
+
+import (
+	"sync"
+	"testing"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestNewSigner(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	tests := []struct {
+		name    string
+		key     ed25519.PrivateKey
+		keyID   string
+		keyURL  string
+		wantErr bool
+	}{
+		{"Normal", key, "", "", false},
+		{"WithKeyID", key, "unique_key_id", "", false},
+		{"WithKeyIDAndKeyURL", key, "unique_key_id", "https://example.com/get_keys", false},
+		{"InvalidKey", ed25519.PrivateKey("test"), "", "", true},
+		{"InvalidKeyWithKeyID", ed25519.PrivateKey("test"), "unique_key_id", "", true},
+		{"KeyURLTooShort", key, "unique_key_id", "https://a.b", true},
+		{"KeyURLMustBeHTTPS", key, "unique_key_id", "ftps://example.com/get_keys", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s *Signer
+			var err error
+			switch {
+			case tt.keyURL != "":
+				s, err = NewSignerWithKeyIDAndKeyURL(tt.key, tt.keyID, tt.keyURL)
+			case tt.keyID != "":
+				s, err = NewSignerWithKeyID(tt.key, tt.keyID)
+			default:
+				s, err = NewSigner(tt.key)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewSigner() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if s.KeyID() != tt.keyID || s.KeyURL() != tt.keyURL {
+				t.Errorf("NewSigner() kid = %q, jku = %q, want %q and %q", s.KeyID(), s.KeyURL(), tt.keyID, tt.keyURL)
+			}
+		})
+	}
+	if _, err := NewSignerWithKeyID(key, ""); err == nil {
+		t.Error("NewSignerWithKeyID() accepted empty key ID")
+	}
+	if _, err := NewSignerWithKeyIDAndKeyURL(key, "", "https://example.com/get_keys"); err == nil {
+		t.Error("NewSignerWithKeyIDAndKeyURL() accepted empty key ID")
+	}
+}
+
+func TestSigner_Sign(t *testing.T) {
+	public1, key1, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	public2, key2, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s1, err := NewSignerWithKeyIDAndKeyURL(key1, "key1", "https://example.com/get_keys")
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	s2, err := NewSignerWithKeyID(key2, "key2")
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+
+	// Two signers have to be usable at the same time from multiple goroutines
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s, public, other := s1, public1, public2
+			if i%2 == 1 {
+				s, public, other = s2, public2, public1
+			}
+			enc, err := s.Sign(map[string]interface{}{"test": i})
+			if err != nil {
+				t.Errorf("Failed to sign token: %s", err.Error())
+				return
+			}
+			dec, err := Decode(string(enc))
+			if err != nil {
+				t.Errorf("Failed to decode token: %s", err.Error())
+				return
+			}
+			if dec.Header.Kid != s.KeyID() || dec.Header.Jku != s.KeyURL() {
+				t.Errorf("Header %+v does not contain key ID and key URL of signer", dec.Header)
+			}
+			if err := dec.Validate(public); err != nil {
+				t.Errorf("Failed to validate token: %s", err.Error())
+			}
+			if err := dec.Validate(other); err == nil {
+				t.Error("Token signed by one signer was validated using the key of the other")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if !public1.Equal(s1.Public()) {
+		t.Error("Public key of signer does not match the private key")
+	}
+	if _, err := s1.Sign("test"); err == nil {
+		t.Error("Signer accepted unsupported content")
+	}
+}
@@ -0,0 +1,182 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/cloudflare/circl/sign/ed448"
+	"golang.org/x/crypto/ed25519"
+)
+
+// SigningMethod signs and verifies tokens using a single algorithm
+// Implementations have to reject keys of types they are not meant for, which binds the algorithm to the key and prevents algorithm confusion
+type SigningMethod interface {
+	// Alg returns the name of the algorithm as used in the alg header
+	Alg() string
+	// Sign returns the signature of data using key
+	Sign(data []byte, key crypto.PrivateKey) ([]byte, error)
+	// Verify returns an error if signature is not a valid signature of data for key
+	Verify(data, signature []byte, key crypto.PublicKey) error
+}
+
+// HMACKey is a shared secret used to sign and verify tokens using HMAC
+// It is a distinct type so a public key supplied as byte slice can never be mistaken for a shared secret
+type HMACKey []byte
+
+// Algorithms implemented by this package which can't be replaced using RegisterSigningMethod
+var builtinSigningMethods = map[string]SigningMethod{
+	SigningMethodEdDSA.Alg(): SigningMethodEdDSA,
+	SigningMethodES256.Alg(): SigningMethodES256,
+	SigningMethodES384.Alg(): SigningMethodES384,
+	SigningMethodRS256.Alg(): SigningMethodRS256,
+	SigningMethodPS256.Alg(): SigningMethodPS256,
+	SigningMethodHS256.Alg(): SigningMethodHS256,
+}
+
+var signingMethods = map[string]SigningMethod{}
+var signingMethodsLock sync.RWMutex
+
+func init() {
+	for alg, m := range builtinSigningMethods {
+		signingMethods[alg] = m
+	}
+}
+
+// RegisterSigningMethod makes m available for validating tokens using the algorithm returned by m.Alg and replaces any method previously registered for it
+// It panics if m uses the algorithm none as unsigned tokens are never accepted or an algorithm implemented by this package, which may not be replaced
+func RegisterSigningMethod(m SigningMethod) {
+	if strings.EqualFold(m.Alg(), "none") {
+		panic("jwt: algorithm none may not be registered")
+	}
+	if _, ok := builtinSigningMethods[m.Alg()]; ok {
+		panic("jwt: algorithm " + m.Alg() + " is implemented by this package and may not be replaced")
+	}
+	signingMethodsLock.Lock()
+	signingMethods[m.Alg()] = m
+	signingMethodsLock.Unlock()
+}
+
+// GetSigningMethod returns the method registered for alg
+func GetSigningMethod(alg string) (SigningMethod, bool) {
+	signingMethodsLock.RLock()
+	defer signingMethodsLock.RUnlock()
+	m, ok := signingMethods[alg]
+	return m, ok
+}
+
+// methodForKey returns the default signing method for the type of key
+// RSA keys use RS256 by default, use Signer.WithAlgorithm for PS256
+func methodForKey(key crypto.PrivateKey) (SigningMethod, error) {
+	switch k := edwardsPrivateKey(key).(type) {
+	case ed25519.PrivateKey, ed448.PrivateKey:
+		return SigningMethodEdDSA, nil
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return SigningMethodES256, nil
+		case elliptic.P384():
+			return SigningMethodES384, nil
+		}
+	case *rsa.PrivateKey:
+		return SigningMethodRS256, nil
+	case HMACKey:
+		return SigningMethodHS256, nil
+	}
+	return nil, errors.New("key is not a valid private key")
+}
+
+// checkPrivateKey returns an error if key is not a valid private key of any supported type
+func checkPrivateKey(key crypto.PrivateKey) error {
+	valid := false
+	switch k := edwardsPrivateKey(key).(type) {
+	case ed25519.PrivateKey:
+		valid = len(k) == ed25519.PrivateKeySize
+	case ed448.PrivateKey:
+		valid = len(k) == ed448.PrivateKeySize
+	case *ecdsa.PrivateKey:
+		valid = k != nil
+	case *rsa.PrivateKey:
+		valid = k != nil
+	case HMACKey:
+		valid = len(k) >= minHMACKeySize
+	}
+	if !valid {
+		return errors.New("key is not a valid private key")
+	}
+	return nil
+}
+
+// checkPublicKey returns an error if key is not a valid public key of any supported type
+func checkPublicKey(key crypto.PublicKey) error {
+	valid := false
+	switch k := edwardsPublicKey(key).(type) {
+	case ed25519.PublicKey:
+		valid = len(k) == ed25519.PublicKeySize
+	case ed448.PublicKey:
+		valid = len(k) == ed448.PublicKeySize
+	case *ecdsa.PublicKey:
+		valid = k != nil
+	case *rsa.PublicKey:
+		valid = k != nil
+	case HMACKey:
+		valid = len(k) >= minHMACKeySize
+	}
+	if !valid {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+// edwardsPublicKey returns a plain byte slice of the size of an Ed25519 or Ed448 public key as ed25519.PublicKey or ed448.PublicKey and any other key unchanged
+// Byte slices were accepted as Ed25519 keys before other key types were supported, so they are still treated as such
+func edwardsPublicKey(key crypto.PublicKey) crypto.PublicKey {
+	if k, ok := key.([]byte); ok {
+		switch len(k) {
+		case ed25519.PublicKeySize:
+			return ed25519.PublicKey(k)
+		case ed448.PublicKeySize:
+			return ed448.PublicKey(k)
+		}
+	}
+	return key
+}
+
+// edwardsPrivateKey returns a plain byte slice of the size of an Ed25519 or Ed448 private key as ed25519.PrivateKey or ed448.PrivateKey and any other key unchanged
+func edwardsPrivateKey(key crypto.PrivateKey) crypto.PrivateKey {
+	if k, ok := key.([]byte); ok {
+		switch len(k) {
+		case ed25519.PrivateKeySize:
+			return ed25519.PrivateKey(k)
+		case ed448.PrivateKeySize:
+			return ed448.PrivateKey(k)
+		}
+	}
+	return key
+}
+
+// publicKeyFor returns the key used to verify signatures made using a valid private key
+// For HMAC this is the shared secret itself
+func publicKeyFor(key crypto.PrivateKey) crypto.PublicKey {
+	if k, ok := key.(HMACKey); ok {
+		return k
+	}
+	return edwardsPrivateKey(key).(crypto.Signer).Public()
+}
+
+// copyPrivateKey returns a copy of a private key stored in a byte slice so it cannot be modified by the caller afterwards
+func copyPrivateKey(key crypto.PrivateKey) crypto.PrivateKey {
+	switch k := edwardsPrivateKey(key).(type) {
+	case ed25519.PrivateKey:
+		return append(ed25519.PrivateKey(nil), k...)
+	case ed448.PrivateKey:
+		return append(ed448.PrivateKey(nil), k...)
+	case HMACKey:
+		return append(HMACKey(nil), k...)
+	}
+	return key
+}
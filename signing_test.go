@@ -0,0 +1,240 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestSigningMethods(t *testing.T) {
+	edPublic, edKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	es256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	es384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	hmacKey := HMACKey("a shared secret that is long enough for HS256")
+
+	tests := []struct {
+		name   string
+		key    crypto.PrivateKey
+		alg    string
+		public crypto.PublicKey
+		wrong  crypto.PublicKey
+	}{
+		{"EdDSA", edKey, "EdDSA", edPublic, HMACKey(edPublic)},
+		{"ES256", es256Key, "ES256", &es256Key.PublicKey, &es384Key.PublicKey},
+		{"ES384", es384Key, "ES384", &es384Key.PublicKey, &es256Key.PublicKey},
+		{"RS256", rsaKey, "RS256", &rsaKey.PublicKey, edPublic},
+		{"PS256", rsaKey, "PS256", &rsaKey.PublicKey, HMACKey("a shared secret that is long enough for HS256")},
+		{"HS256", hmacKey, "HS256", hmacKey, HMACKey("a different secret that is long enough too")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSigner(tt.key)
+			if err != nil {
+				t.Fatalf("Failed to create signer: %s", err.Error())
+			}
+			if s.Algorithm() != tt.alg {
+				s, err = s.WithAlgorithm(tt.alg)
+				if err != nil {
+					t.Fatalf("Failed to change algorithm of signer: %s", err.Error())
+				}
+			}
+			enc, err := s.Sign(RegisteredClaims{Subject: "test"})
+			if err != nil {
+				t.Fatalf("Failed to sign token: %s", err.Error())
+			}
+			dec, err := Decode(string(enc))
+			if err != nil {
+				t.Fatalf("Failed to decode token: %s", err.Error())
+			}
+			if dec.Header.Alg != tt.alg {
+				t.Errorf("Token has algorithm %s, want %s", dec.Header.Alg, tt.alg)
+			}
+			if err := dec.Validate(tt.public); err != nil {
+				t.Errorf("Failed to validate token: %s", err.Error())
+			}
+			if err := dec.Validate(tt.wrong); err == nil {
+				t.Errorf("Token was validated using wrong key %T", tt.wrong)
+			}
+		})
+	}
+}
+
+func TestSigningMethods_AlgorithmConfusion(t *testing.T) {
+	edPublic, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	esKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+
+	// An attacker knowing the public key signs a token using HMAC with the public key as secret
+	forge := func(secret []byte) string {
+		s, err := NewSigner(HMACKey(secret))
+		if err != nil {
+			t.Fatalf("Failed to create signer: %s", err.Error())
+		}
+		enc, err := s.Sign(RegisteredClaims{Subject: "admin"})
+		if err != nil {
+			t.Fatalf("Failed to sign token: %s", err.Error())
+		}
+		return string(enc)
+	}
+	pemPublic, _ := MarshalPublicKeyPEM(edPublic)
+	tests := []struct {
+		name  string
+		token string
+		key   crypto.PublicKey
+	}{
+		{"Ed25519", forge(edPublic), edPublic},
+		{"Ed25519Bytes", forge(edPublic), []byte(edPublic)},
+		{"ECDSA", forge(elliptic.Marshal(elliptic.P256(), esKey.X, esKey.Y)), &esKey.PublicKey},
+		{"PEM", forge(pemPublic), edPublic},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec, err := Decode(tt.token)
+			if err != nil {
+				t.Fatalf("Failed to decode token: %s", err.Error())
+			}
+			if err := dec.Validate(tt.key); err == nil {
+				t.Error("Forged token was validated")
+			}
+		})
+	}
+}
+
+type testSigningMethod struct{}
+
+func (testSigningMethod) Alg() string {
+	return "TEST"
+}
+
+func (testSigningMethod) Sign(data []byte, key crypto.PrivateKey) ([]byte, error) {
+	if _, ok := key.(HMACKey); !ok {
+		return nil, errors.New("key is not a valid TEST key")
+	}
+	return []byte("signature"), nil
+}
+
+func (testSigningMethod) Verify(data, signature []byte, key crypto.PublicKey) error {
+	if string(signature) != "signature" {
+		return errors.New("hash does not match content")
+	}
+	return nil
+}
+
+func TestRegisterSigningMethod(t *testing.T) {
+	if _, ok := GetSigningMethod("TEST"); ok {
+		t.Fatal("GetSigningMethod() returned method that has not been registered")
+	}
+	RegisterSigningMethod(testSigningMethod{})
+	defer func() {
+		signingMethodsLock.Lock()
+		delete(signingMethods, "TEST")
+		signingMethodsLock.Unlock()
+	}()
+	m, ok := GetSigningMethod("TEST")
+	if !ok || m.Alg() != "TEST" {
+		t.Fatal("GetSigningMethod() did not return registered method")
+	}
+
+	key := HMACKey("a shared secret that is long enough for HS256")
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	if _, err := s.WithAlgorithm("UNKNOWN"); err == nil {
+		t.Error("Signer.WithAlgorithm() accepted unknown algorithm")
+	}
+	if _, err := s.WithAlgorithm("EdDSA"); err == nil {
+		t.Error("Signer.WithAlgorithm() accepted algorithm that does not support the key")
+	}
+	s, err = s.WithAlgorithm("TEST")
+	if err != nil {
+		t.Fatalf("Failed to change algorithm of signer: %s", err.Error())
+	}
+	enc, err := s.Sign(RegisteredClaims{Subject: "test"})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+	dec, err := Decode(string(enc))
+	if err != nil {
+		t.Fatalf("Failed to decode token: %s", err.Error())
+	}
+	if err := dec.Validate(key); err != nil {
+		t.Errorf("Failed to validate token using registered method: %s", err.Error())
+	}
+
+	// Verifiers can restrict the accepted algorithms
+	v, err := NewVerifier(key, WithAlgorithms("HS256"))
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %s", err.Error())
+	}
+	if _, err := v.Verify(string(enc)); err == nil {
+		t.Error("Verifier.Verify() accepted algorithm that is not allowed")
+	}
+}
+
+func TestNewSigner_Keys(t *testing.T) {
+	p224, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	small, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	tests := []struct {
+		name string
+		key  crypto.PrivateKey
+	}{
+		{"UnsupportedCurve", p224},
+		{"NilECDSA", (*ecdsa.PrivateKey)(nil)},
+		{"NilRSA", (*rsa.PrivateKey)(nil)},
+		{"EmptyHMAC", HMACKey{}},
+		{"ShortHMAC", HMACKey("short")},
+		{"Bytes", []byte("test")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSigner(tt.key); err == nil {
+				t.Error("NewSigner() accepted invalid key")
+			}
+		})
+	}
+
+	// Keys that are too weak are rejected when signing
+	s, err := NewSigner(small)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	if _, err := s.Sign(RegisteredClaims{}); err == nil {
+		t.Error("Signer.Sign() accepted weak RSA key")
+	}
+
+	// Short shared secrets are rejected for verification as well
+	if _, err := NewVerifier(HMACKey("short")); err == nil {
+		t.Error("NewVerifier() accepted short HMAC key")
+	}
+}
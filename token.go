@@ -0,0 +1,76 @@
+package jwt
+
+import (
+	"crypto"
+	"encoding/json"
+	"time"
+)
+
+// Token contains the header of a JSON web token, its content decoded into a caller supplied type and the decoded hash
+// The registered claims are checked during validation when T embeds RegisteredClaims or otherwise contains them
+type Token[T any] struct {
+	Header Header
+	Claims T
+	Hash   []byte
+	raw    []byte // Encoded header and content exactly as they appeared in the decoded token
+}
+
+// DecodeInto decodes a string to a Token with the content decoded directly into T
+func DecodeInto[T any](token string) (data Token[T], err error) {
+	header, content, hash, raw, err := decodeSections(token)
+	if err != nil {
+		return
+	}
+	data.Header = header
+	err = json.Unmarshal(content, &data.Claims)
+	if err != nil {
+		err = malformed(err)
+		return
+	}
+	data.Hash = hash
+	data.raw = raw
+	return
+}
+
+// VerifyInto decodes token into a Token with its content decoded into T and returns it only if v considers it valid
+func VerifyInto[T any](v *Verifier, token string) (Token[T], error) {
+	t, err := DecodeInto[T](token)
+	if err != nil {
+		return Token[T]{}, err
+	}
+	jwt := t.jwt()
+	err = v.verify(&jwt)
+	if err != nil {
+		return Token[T]{}, err
+	}
+	return t, nil
+}
+
+// Validate returns an error when the hash does not match the content or the token has expired or is not valid, yet
+// It behaves exactly like Validate on a JWT
+func (t *Token[T]) Validate(key crypto.PublicKey) error {
+	jwt := t.jwt()
+	return jwt.Validate(key)
+}
+
+// ValidateWithClock behaves like Validate but uses clock for the current time and allows exp, nbf and iat to be off by up to leeway
+func (t *Token[T]) ValidateWithClock(key crypto.PublicKey, clock Clock, leeway time.Duration) error {
+	jwt := t.jwt()
+	return jwt.ValidateWithClock(key, clock, leeway)
+}
+
+// VerifyWithKeySet behaves like Validate but uses the keys provided by ks for the header of the token
+func (t *Token[T]) VerifyWithKeySet(ks KeySet) error {
+	jwt := t.jwt()
+	return jwt.VerifyWithKeySet(ks)
+}
+
+// VerifyWithKeySetAndClock behaves like VerifyWithKeySet but uses clock for the current time and allows exp, nbf and iat to be off by up to leeway
+func (t *Token[T]) VerifyWithKeySetAndClock(ks KeySet, clock Clock, leeway time.Duration) error {
+	jwt := t.jwt()
+	return jwt.VerifyWithKeySetAndClock(ks, clock, leeway)
+}
+
+func (t *Token[T]) jwt() JWT {
+	return JWT{t.Header, t.Claims, t.Hash, t.raw}
+}
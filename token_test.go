@@ -0,0 +1,124 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+type testClaims struct {
+	RegisteredClaims
+	Name  string `json:"name"`
+	Admin bool   `json:"admin"`
+}
+
+func TestDecodeInto(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSignerWithKeyID(key, "key")
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	enc, err := s.Sign(testClaims{RegisteredClaims{Subject: "1234", ExpiresAt: NewNumericDate(time.Now().Add(time.Minute))}, "John Doe", true})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+
+	tok, err := DecodeInto[testClaims](string(enc))
+	if err != nil {
+		t.Fatalf("Failed to decode token: %s", err.Error())
+	}
+	if tok.Header.Kid != "key" || tok.Claims.Subject != "1234" || tok.Claims.Name != "John Doe" || !tok.Claims.Admin {
+		t.Errorf("DecodeInto() = %+v, does not match original token", tok)
+	}
+	if err := tok.Validate(public); err != nil {
+		t.Errorf("Failed to validate decoded token: %s", err.Error())
+	}
+
+	// Changes to the claims after decoding must not affect validation
+	tok.Claims.Admin = false
+	if err := tok.Validate(public); err != nil {
+		t.Errorf("Failed to validate decoded token after modifying claims: %s", err.Error())
+	}
+
+	if _, err := DecodeInto[testClaims]("A.B"); err == nil {
+		t.Error("DecodeInto() accepted invalid token")
+	}
+	if _, err := DecodeInto[int](string(enc)); err == nil {
+		t.Error("DecodeInto() accepted content that does not match the type")
+	}
+}
+
+func TestToken_Validate(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	enc, err := s.Sign(testClaims{RegisteredClaims: RegisteredClaims{ExpiresAt: NewNumericDate(time.Now().Add(-time.Minute))}})
+	if err != nil {
+		t.Fatalf("Failed to sign token: %s", err.Error())
+	}
+	tok, err := DecodeInto[testClaims](string(enc))
+	if err != nil {
+		t.Fatalf("Failed to decode token: %s", err.Error())
+	}
+	if err := tok.Validate(public); err == nil {
+		t.Error("Expired token not detected by validate")
+	}
+
+	// Tokens that have not been signed are never valid
+	tok = Token[testClaims]{Header: Header{Typ: "JWT", Alg: "EdDSA"}, Claims: testClaims{Name: "test"}}
+	if err := tok.Validate(public); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("Token.Validate() error = %v on token that has not been signed, want %v", err, ErrSignatureInvalid)
+	}
+}
+
+func TestVerifyInto(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	v, err := NewVerifier(public, WithIssuer("issuer"))
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %s", err.Error())
+	}
+	tests := []struct {
+		name    string
+		claims  testClaims
+		wantErr bool
+	}{
+		{"Valid", testClaims{RegisteredClaims{Issuer: "issuer"}, "John Doe", false}, false},
+		{"WrongIssuer", testClaims{RegisteredClaims{Issuer: "other"}, "John Doe", false}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc, err := s.Sign(tt.claims)
+			if err != nil {
+				t.Fatalf("Failed to sign token: %s", err.Error())
+			}
+			got, err := VerifyInto[testClaims](v, string(enc))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyInto() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got.Claims.Name != tt.claims.Name {
+				t.Errorf("VerifyInto() claims = %+v, want %+v", got.Claims, tt.claims)
+			}
+		})
+	}
+	if _, err := VerifyInto[testClaims](v, "test"); err == nil {
+		t.Error("VerifyInto() accepted invalid token")
+	}
+}
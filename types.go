@@ -2,10 +2,22 @@ package jwt
 
 // Header contains the header data of a JSON web token
 type Header struct {
-	Typ string `json:"typ"`
-	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+	Alg string `json:"alg,omitempty"`
 	Kid string `json:"kid,omitempty"`
 	Jku string `json:"jku,omitempty"`
+	Cty string `json:"cty,omitempty"`
+	// Crit lists header parameters that have to be understood by the recipient, the only one supported is b64
+	Crit []string `json:"crit,omitempty"`
+	// B64 is set to false for JWS with an unencoded payload as defined in RFC 7797
+	B64 *bool `json:"b64,omitempty"`
+	// Enc, Epk, Apu and Apv are only used for JWE and contain the content encryption algorithm, the ephemeral public key and the agreement party info
+	Enc string `json:"enc,omitempty"`
+	Epk *JWK   `json:"epk,omitempty"`
+	Apu string `json:"apu,omitempty"`
+	Apv string `json:"apv,omitempty"`
+	// Zip is the compression algorithm of a JWE, compressed JWE are not supported and therefore rejected
+	Zip string `json:"zip,omitempty"`
 }
 
 // JWT contains the header and content of a JSON web token as well as the decoded hash
@@ -13,4 +25,5 @@ type JWT struct {
 	Header  Header
 	Content interface{}
 	Hash    []byte
+	raw     []byte // Encoded header and content exactly as they appeared in the decoded token
 }
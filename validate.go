@@ -1,54 +1,127 @@
 package jwt
 
 import (
+	"crypto"
 	"errors"
-	"fmt"
-	"math"
+	"strings"
 	"time"
-
-	"golang.org/x/crypto/ed25519"
 )
 
-// Validate returns an error when the hash does not match the content
-func (jwt *JWT) Validate(key ed25519.PublicKey) error {
+// Validate returns an error when the hash does not match the content or the token has expired or is not valid, yet
+// Tokens issued in the future are considered not valid, yet
+// The errors match ErrSignatureInvalid, ErrExpired and ErrNotYetValid respectively and tokens using the algorithm none are always rejected with ErrAlgNotAllowed
+// Tokens without a hash, such as those created using New and not decoded, are rejected with ErrSignatureInvalid
+// The signing method is chosen using the alg header and has to accept the type of key, so tokens signed using EdDSA can only be validated using an ed25519.PublicKey or ed448.PublicKey
+// The registered claims are checked regardless of whether the content is a map or a struct
+// For decoded tokens the hash is checked against the header and content as they appeared in the token, so changes made to Header or Content after decoding are not taken into account
+func (jwt *JWT) Validate(key crypto.PublicKey) error {
+	return jwt.ValidateWithClock(key, systemClock{}, 0)
+}
+
+// ValidateWithClock behaves like Validate but uses clock for the current time and allows exp, nbf and iat to be off by up to leeway to account for clock skew between hosts
+func (jwt *JWT) ValidateWithClock(key crypto.PublicKey, clock Clock, leeway time.Duration) error {
+	err := jwt.validateHash(key)
+	if err != nil {
+		return err
+	}
+	return jwt.validateTime(clock.Now().UTC(), leeway)
+}
+
+// VerifyWithKeySet behaves like Validate but uses the keys provided by ks for the header of the token
+// The token is valid if its hash matches any of these keys
+func (jwt *JWT) VerifyWithKeySet(ks KeySet) error {
+	return jwt.VerifyWithKeySetAndClock(ks, systemClock{}, 0)
+}
+
+// VerifyWithKeySetAndClock behaves like VerifyWithKeySet but uses clock for the current time and allows exp, nbf and iat to be off by up to leeway like ValidateWithClock
+func (jwt *JWT) VerifyWithKeySetAndClock(ks KeySet, clock Clock, leeway time.Duration) error {
+	err := jwt.validateHashWithKeySet(ks)
+	if err != nil {
+		return err
+	}
+	return jwt.validateTime(clock.Now().UTC(), leeway)
+}
+
+// validateTime returns an error when the token has expired, is not valid, yet or was issued in the future
+func (jwt *JWT) validateTime(now time.Time, leeway time.Duration) error {
+	content, err := jwt.contentJSON()
+	if err != nil {
+		return err
+	}
+	claims, err := parseRegisteredClaims(content)
+	if err != nil {
+		return malformed(err)
+	}
+	return checkTime(claims, now, leeway)
+}
+
+// checkTime returns an error when the claims have expired at now, are not valid, yet or were issued in the future while allowing each of these to be off by up to leeway
+// Tokens issued in the future are considered not valid, yet
+func checkTime(claims RegisteredClaims, now time.Time, leeway time.Duration) error {
+	if claims.ExpiresAt != nil && !now.Before(claims.ExpiresAt.Add(leeway)) {
+		return &ExpiredError{claims.ExpiresAt.Time}
+	}
+	if claims.NotBefore != nil && now.Add(leeway).Before(claims.NotBefore.Time) {
+		return &NotYetValidError{claims.NotBefore.Time}
+	}
+	if claims.IssuedAt != nil && now.Add(leeway).Before(claims.IssuedAt.Time) {
+		return &NotYetValidError{claims.IssuedAt.Time}
+	}
+	return nil
+}
+
+// validateHashWithKeySet checks the hash against all keys provided by ks and succeeds if any of them matches
+func (jwt *JWT) validateHashWithKeySet(ks KeySet) error {
+	return verifyWithKeySet(ks, jwt.Header, jwt.validateHash)
+}
+
+// validateHash checks the type, algorithm and hash of the token but ignores its content
+func (jwt *JWT) validateHash(key crypto.PublicKey) error {
 	// Make sure the key is actually valid
-	if len(key) != ed25519.PublicKeySize {
-		return errors.New("key is not a valid public key")
+	err := checkPublicKey(key)
+	if err != nil {
+		return err
 	}
 	// Check token type and algorithm
 	if jwt.Header.Typ != "JWT" {
-		return errors.New("header indicates token is not JWT")
-	}
-	if jwt.Header.Alg != "EdDSA" {
-		return fmt.Errorf("could not validate JWT - algorithm %s not supported", jwt.Header.Alg)
+		return malformed(errors.New("header indicates token is not JWT"))
 	}
-
-	// Encode header and content
-	header := encodeHeader(jwt.Header)
-	content, err := encode(jwt.Content)
+	_, err = methodForAlg(jwt.Header.Alg)
 	if err != nil {
 		return err
 	}
-	data := join(header, content)
 
 	// Check the hash using the public key
-	if jwt.Hash != nil && !ed25519.Verify(key, data, jwt.Hash) {
-		return errors.New("hash does not match content")
+	// Tokens that have not been signed are never valid
+	if len(jwt.Hash) == 0 {
+		return ErrSignatureInvalid
 	}
+	j, err := jwt.jws()
+	if err != nil {
+		return err
+	}
+	return j.Verify(key)
+}
 
-	// Validate expiry and not before if they exist
-	if m, ok := jwt.Content.(map[string]interface{}); ok {
-		if exp, ok := m["exp"].(float64); ok {
-			if time.Unix(int64(math.Round(exp)), 0).Before(time.Now().UTC()) {
-				return errors.New("jwt has expired")
-			}
-		}
-		if nbf, ok := m["nbf"].(float64); ok {
-			if time.Unix(int64(math.Round(nbf)), 0).After(time.Now().UTC()) {
-				return errors.New("jwt is not valid, yet")
-			}
-		}
+// jws returns the token as JWS
+// The encoded header and content of decoded tokens are used as is and only encoded for tokens created using New
+func (jwt *JWT) jws() (JWS, error) {
+	content, err := jwt.contentJSON()
+	if err != nil {
+		return JWS{}, malformed(err)
 	}
+	return JWS{Header: jwt.Header, Payload: content, Signature: jwt.Hash, raw: jwt.raw}, nil
+}
 
-	return nil
+// methodForAlg returns the signing method registered for alg
+// Unsigned tokens are never accepted, regardless of the registered methods
+func methodForAlg(alg string) (SigningMethod, error) {
+	if strings.EqualFold(alg, "none") {
+		return nil, &AlgorithmError{Alg: alg}
+	}
+	method, ok := GetSigningMethod(alg)
+	if !ok {
+		return nil, &AlgorithmError{Alg: alg, Unsupported: true}
+	}
+	return method, nil
 }
@@ -0,0 +1,126 @@
+package jwt
+
+import (
+	"crypto"
+	"encoding/json"
+)
+
+// Claims contains the content of a token that has not been signed, yet
+// Claims can only be turned into a token by signing them and are never considered valid on their own
+type Claims struct {
+	content interface{}
+}
+
+// NewClaims returns Claims containing content
+// Content has to be either a struct or a map with string keys
+func NewClaims(content interface{}) (Claims, error) {
+	_, err := New(content)
+	if err != nil {
+		return Claims{}, err
+	}
+	return Claims{content}, nil
+}
+
+// Content returns the content of the claims
+func (c Claims) Content() interface{} {
+	return c.content
+}
+
+// Sign signs the claims using s and returns the encoded token
+func (c Claims) Sign(s *Signer) ([]byte, error) {
+	return s.Sign(c.content)
+}
+
+// ParsedToken contains a token that has been decoded but whose hash and claims have not been verified
+// Its content is only accessible after verification, which returns a VerifiedToken
+type ParsedToken struct {
+	jwt JWT
+}
+
+// Parse decodes token to a ParsedToken without verifying it
+// Errors caused by the token itself match ErrMalformed
+func Parse(token string) (ParsedToken, error) {
+	t, err := Decode(token)
+	if err != nil {
+		return ParsedToken{}, err
+	}
+	return ParsedToken{t}, nil
+}
+
+// Header returns the header of the token
+// It has not been verified and should only be used to choose the key to verify the token with
+func (p ParsedToken) Header() Header {
+	return p.jwt.Header
+}
+
+// Verify checks the token exactly like Validate on a JWT and returns it as VerifiedToken if it is valid
+func (p ParsedToken) Verify(key crypto.PublicKey) (VerifiedToken, error) {
+	t := p.jwt
+	err := t.Validate(key)
+	if err != nil {
+		return VerifiedToken{}, err
+	}
+	return verified(&t)
+}
+
+// VerifyWithKeySet behaves like Verify but uses the keys provided by ks for the header of the token
+func (p ParsedToken) VerifyWithKeySet(ks KeySet) (VerifiedToken, error) {
+	t := p.jwt
+	err := t.VerifyWithKeySet(ks)
+	if err != nil {
+		return VerifiedToken{}, err
+	}
+	return verified(&t)
+}
+
+// VerifiedToken contains a token whose hash and claims have been verified
+// It can only be obtained from a successful verification, so functions accepting a VerifiedToken never have to check it again
+// The zero value contains neither a header nor content
+type VerifiedToken struct {
+	header  Header
+	content interface{}
+	payload []byte // Content as JSON exactly as it appeared in the token
+}
+
+// verified returns t as VerifiedToken and must only be called after t has been verified
+func verified(t *JWT) (VerifiedToken, error) {
+	payload, err := t.contentJSON()
+	if err != nil {
+		return VerifiedToken{}, malformed(err)
+	}
+	return VerifiedToken{t.Header, t.Content, payload}, nil
+}
+
+// Header returns the header of the token
+func (t VerifiedToken) Header() Header {
+	return t.header
+}
+
+// Content returns the content of the token decoded into an interface{}
+func (t VerifiedToken) Content() interface{} {
+	return t.content
+}
+
+// RegisteredClaims returns the registered claims contained in the token
+func (t VerifiedToken) RegisteredClaims() RegisteredClaims {
+	c, _ := parseRegisteredClaims(t.payload) // Error is safe to ignore as the claims have been parsed successfully during verification
+	return c
+}
+
+// Unmarshal decodes the content of the token into v
+func (t VerifiedToken) Unmarshal(v interface{}) error {
+	return json.Unmarshal(t.payload, v)
+}
+
+// VerifyToken behaves like Verify but returns the token as VerifiedToken
+func (v *Verifier) VerifyToken(token string) (VerifiedToken, error) {
+	t, err := Decode(token)
+	if err != nil {
+		return VerifiedToken{}, err
+	}
+	err = v.verify(&t)
+	if err != nil {
+		return VerifiedToken{}, err
+	}
+	return verified(&t)
+}
@@ -0,0 +1,119 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestParsedToken_Verify(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	wrong, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSignerWithKeyID(key, "key")
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	c, err := NewClaims(testClaims{RegisteredClaims: RegisteredClaims{Subject: "1234"}, Name: "John Doe"})
+	if err != nil {
+		t.Fatalf("Failed to create claims: %s", err.Error())
+	}
+	enc, err := c.Sign(s)
+	if err != nil {
+		t.Fatalf("Failed to sign claims: %s", err.Error())
+	}
+
+	p, err := Parse(string(enc))
+	if err != nil {
+		t.Fatalf("Failed to parse token: %s", err.Error())
+	}
+	if p.Header().Kid != "key" {
+		t.Errorf("ParsedToken.Header() = %+v, want key ID key", p.Header())
+	}
+	if _, err := p.Verify(wrong); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("ParsedToken.Verify() error = %v using wrong key, want %v", err, ErrSignatureInvalid)
+	}
+	ks := NewMemoryKeySet()
+	if _, err := p.VerifyWithKeySet(ks); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("ParsedToken.VerifyWithKeySet() error = %v, want %v", err, ErrUnknownKey)
+	}
+	if err := ks.Add("key", public); err != nil {
+		t.Fatalf("Failed to add key to set: %s", err.Error())
+	}
+	for name, verify := range map[string]func() (VerifiedToken, error){
+		"Verify":           func() (VerifiedToken, error) { return p.Verify(public) },
+		"VerifyWithKeySet": func() (VerifiedToken, error) { return p.VerifyWithKeySet(ks) },
+	} {
+		tok, err := verify()
+		if err != nil {
+			t.Fatalf("ParsedToken.%s() error = %v", name, err)
+		}
+		if tok.Header().Kid != "key" || tok.RegisteredClaims().Subject != "1234" || tok.Content().(map[string]interface{})["name"] != "John Doe" {
+			t.Errorf("ParsedToken.%s() = %+v, does not match original token", name, tok)
+		}
+		var got testClaims
+		if err := tok.Unmarshal(&got); err != nil || got.Name != "John Doe" {
+			t.Errorf("VerifiedToken.Unmarshal() = %+v, %v", got, err)
+		}
+	}
+
+	if _, err := Parse("A.B"); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Parse() error = %v, want %v", err, ErrMalformed)
+	}
+	if _, err := NewClaims("test"); err == nil {
+		t.Error("NewClaims() accepted content that is neither a struct nor a map")
+	}
+}
+
+func TestVerifier_VerifyToken(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	v, err := NewVerifier(public, WithIssuer("issuer"))
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %s", err.Error())
+	}
+	now := time.Now()
+	tests := []struct {
+		name    string
+		claims  RegisteredClaims
+		wantErr error
+	}{
+		{"Valid", RegisteredClaims{Issuer: "issuer", Subject: "1234"}, nil},
+		{"Expired", RegisteredClaims{Issuer: "issuer", ExpiresAt: NewNumericDate(now.Add(-time.Minute))}, ErrExpired},
+		{"WrongIssuer", RegisteredClaims{Issuer: "other"}, ErrClaimInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc, err := s.Sign(tt.claims)
+			if err != nil {
+				t.Fatalf("Failed to sign token: %s", err.Error())
+			}
+			got, err := v.VerifyToken(string(enc))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verifier.VerifyToken() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got.RegisteredClaims().Subject != tt.claims.Subject {
+				t.Errorf("Verifier.VerifyToken() claims = %+v, want %+v", got.RegisteredClaims(), tt.claims)
+			}
+		})
+	}
+
+	// Unsigned tokens are rejected even if the algorithm is supported
+	unsigned := string(join(encodeHeader(Header{Typ: "JWT", Alg: "EdDSA"}), b64encode([]byte(`{"iss":"issuer"}`)))) + "."
+	if _, err := v.VerifyToken(unsigned); err == nil {
+		t.Error("Verifier.VerifyToken() accepted unsigned token")
+	}
+}
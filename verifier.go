@@ -0,0 +1,222 @@
+package jwt
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Verifier decodes tokens and only returns them when their hash is valid and their content passes all configured rules
+// A Verifier is immutable and may therefore be used by multiple goroutines at once
+type Verifier struct {
+	keys       KeySet
+	algorithms []string
+	issuers    []string
+	audiences  []string
+	leeway     time.Duration
+	clock      Clock
+	maxAge     time.Duration
+	required   []string
+	replay     ReplayStore
+}
+
+// VerifierOption configures an additional rule checked by a Verifier
+type VerifierOption func(*Verifier)
+
+// WithAlgorithms only accepts tokens signed using one of algs
+// By default all registered algorithms are accepted as long as the key supports them
+func WithAlgorithms(algs ...string) VerifierOption {
+	return func(v *Verifier) {
+		v.algorithms = append(v.algorithms, algs...)
+	}
+}
+
+// WithIssuer requires the iss claim to be set to one of issuers
+func WithIssuer(issuers ...string) VerifierOption {
+	return func(v *Verifier) {
+		v.issuers = append(v.issuers, issuers...)
+	}
+}
+
+// WithAudience requires the aud claim to contain at least one of audiences
+func WithAudience(audiences ...string) VerifierOption {
+	return func(v *Verifier) {
+		v.audiences = append(v.audiences, audiences...)
+	}
+}
+
+// WithLeeway allows exp, nbf and iat to be off by up to leeway to account for clock skew between hosts
+func WithLeeway(leeway time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.leeway = leeway
+	}
+}
+
+// WithClock uses clock instead of the system clock to determine the current time
+func WithClock(clock Clock) VerifierOption {
+	return func(v *Verifier) {
+		v.clock = clock
+	}
+}
+
+// WithMaxAge requires the iat claim to be set and rejects tokens that were issued more than maxAge ago
+func WithMaxAge(maxAge time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.maxAge = maxAge
+	}
+}
+
+// WithRequiredClaims requires all of claims to be present in the content
+func WithRequiredClaims(claims ...string) VerifierOption {
+	return func(v *Verifier) {
+		v.required = append(v.required, claims...)
+	}
+}
+
+// WithReplayStore rejects tokens whose jti claim has been recorded in store before with ErrReplayed
+// Tokens are only recorded once all other rules pass and have to contain jti as well as exp or iat and a maximum age, so their ID can be forgotten once they have expired
+func WithReplayStore(store ReplayStore) VerifierOption {
+	return func(v *Verifier) {
+		v.replay = store
+	}
+}
+
+// NewVerifier returns a new Verifier that validates tokens using key and checks all rules configured by opts
+// Expiry and not before are always checked when they are set
+func NewVerifier(key crypto.PublicKey, opts ...VerifierOption) (*Verifier, error) {
+	err := checkPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifierWithKeySet(singleKey{key}, opts...)
+}
+
+// NewVerifierWithKeySet returns a new Verifier that validates tokens using the keys provided by ks and checks all rules configured by opts
+func NewVerifierWithKeySet(ks KeySet, opts ...VerifierOption) (*Verifier, error) {
+	if ks == nil {
+		return nil, errors.New("key set may not be nil")
+	}
+	v := &Verifier{keys: ks, clock: systemClock{}}
+	for _, opt := range opts {
+		opt(v)
+	}
+	if v.leeway < 0 || v.maxAge < 0 {
+		return nil, errors.New("leeway and maximum age may not be negative")
+	}
+	if v.clock == nil {
+		return nil, errors.New("clock may not be nil")
+	}
+	return v, nil
+}
+
+// Verify decodes token and returns it only if its hash is valid and its content passes all rules
+// Rules that are not met result in a ClaimError, an ExpiredError or a NotYetValidError
+func (v *Verifier) Verify(token string) (JWT, error) {
+	t, err := Decode(token)
+	if err != nil {
+		return JWT{}, err
+	}
+	err = v.verify(&t)
+	if err != nil {
+		return JWT{}, err
+	}
+	return t, nil
+}
+
+func (v *Verifier) verify(t *JWT) error {
+	if len(v.algorithms) > 0 && !contains(v.algorithms, t.Header.Alg) {
+		return &AlgorithmError{Alg: t.Header.Alg}
+	}
+	err := t.validateHashWithKeySet(v.keys)
+	if err != nil {
+		return err
+	}
+	content, err := t.contentJSON()
+	if err != nil {
+		return err
+	}
+	return v.validateClaims(content, v.clock.Now().UTC())
+}
+
+func (v *Verifier) validateClaims(content []byte, now time.Time) error {
+	if len(v.required) > 0 {
+		var all map[string]json.RawMessage
+		if isJSONObject(content) {
+			err := json.Unmarshal(content, &all)
+			if err != nil {
+				return malformed(err)
+			}
+		}
+		for _, c := range v.required {
+			if _, ok := all[c]; !ok {
+				return &ClaimError{c, ErrClaimMissing}
+			}
+		}
+	}
+
+	claims, err := parseRegisteredClaims(content)
+	if err != nil {
+		return malformed(err)
+	}
+	err = checkTime(claims, now, v.leeway)
+	if err != nil {
+		return err
+	}
+	if v.maxAge > 0 {
+		if claims.IssuedAt == nil {
+			return &ClaimError{"iat", ErrClaimMissing}
+		}
+		if now.Sub(claims.IssuedAt.Time) > v.maxAge+v.leeway {
+			return &ExpiredError{claims.IssuedAt.Add(v.maxAge)}
+		}
+	}
+
+	if len(v.issuers) > 0 && !contains(v.issuers, claims.Issuer) {
+		return &ClaimError{"iss", ErrClaimInvalid}
+	}
+	if len(v.audiences) > 0 && !claims.Audience.Contains(v.audiences...) {
+		return &ClaimError{"aud", ErrClaimInvalid}
+	}
+
+	if v.replay != nil {
+		return v.checkReplay(claims)
+	}
+	return nil
+}
+
+// checkReplay records the issuer and ID of the token in the replay store and returns ErrReplayed if they have been recorded before
+// The ID is kept until the token expires including leeway, either by exp or by exceeding the maximum age, whichever comes first
+func (v *Verifier) checkReplay(claims RegisteredClaims) error {
+	if claims.ID == "" {
+		return &ClaimError{"jti", ErrClaimMissing}
+	}
+	var expiry time.Time
+	if claims.ExpiresAt != nil {
+		expiry = claims.ExpiresAt.Add(v.leeway)
+	}
+	// The issue date has already been checked to be present when a maximum age is set
+	if v.maxAge > 0 && (expiry.IsZero() || claims.IssuedAt.Add(v.maxAge+v.leeway).Before(expiry)) {
+		expiry = claims.IssuedAt.Add(v.maxAge + v.leeway)
+	}
+	if expiry.IsZero() {
+		return &ClaimError{"exp", ErrClaimMissing}
+	}
+	seen, err := v.replay.Seen(claims.Issuer+"\x00"+claims.ID, expiry)
+	if err != nil {
+		return err
+	}
+	if seen {
+		return ErrReplayed
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	public, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	wrongKey, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	s, err := NewSigner(key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err.Error())
+	}
+	now := time.Now().UTC()
+	tests := []struct {
+		name    string
+		key     ed25519.PublicKey
+		content map[string]interface{}
+		opts    []VerifierOption
+		wantErr bool
+	}{
+		{"NoRules", public, map[string]interface{}{"sub": "test"}, nil, false},
+		{"WrongKey", wrongKey, map[string]interface{}{"sub": "test"}, nil, true},
+		{"Expired", public, map[string]interface{}{"exp": now.Add(-time.Minute).Unix()}, nil, true},
+		{"ExpiredWithinLeeway", public, map[string]interface{}{"exp": now.Add(-time.Minute).Unix()}, []VerifierOption{WithLeeway(2 * time.Minute)}, false},
+		{"NotValidYet", public, map[string]interface{}{"nbf": now.Add(time.Minute).Unix()}, nil, true},
+		{"NotValidYetWithinLeeway", public, map[string]interface{}{"nbf": now.Add(time.Minute).Unix()}, []VerifierOption{WithLeeway(2 * time.Minute)}, false},
+		{"Issuer", public, map[string]interface{}{"iss": "issuer"}, []VerifierOption{WithIssuer("issuer")}, false},
+		{"WrongIssuer", public, map[string]interface{}{"iss": "other"}, []VerifierOption{WithIssuer("issuer")}, true},
+		{"MissingIssuer", public, map[string]interface{}{}, []VerifierOption{WithIssuer("issuer")}, true},
+		{"IssuerOneOf", public, map[string]interface{}{"iss": "issuer2"}, []VerifierOption{WithIssuer("issuer1", "issuer2")}, false},
+		{"WrongIssuerOneOf", public, map[string]interface{}{"iss": "issuer3"}, []VerifierOption{WithIssuer("issuer1", "issuer2")}, true},
+		{"AudienceString", public, map[string]interface{}{"aud": "service"}, []VerifierOption{WithAudience("service")}, false},
+		{"AudienceArray", public, map[string]interface{}{"aud": []string{"other", "service"}}, []VerifierOption{WithAudience("service")}, false},
+		{"AudienceOneOf", public, map[string]interface{}{"aud": "service2"}, []VerifierOption{WithAudience("service1", "service2")}, false},
+		{"WrongAudience", public, map[string]interface{}{"aud": []string{"other"}}, []VerifierOption{WithAudience("service")}, true},
+		{"MissingAudience", public, map[string]interface{}{}, []VerifierOption{WithAudience("service")}, true},
+		{"AudienceArraySingleValue", public, map[string]interface{}{"aud": []string{"service"}}, []VerifierOption{WithAudience("service")}, false},
+		{"SiblingAudience", public, map[string]interface{}{"aud": []string{"service1"}}, []VerifierOption{WithAudience("service2")}, true},
+		{"MaxAge", public, map[string]interface{}{"iat": now.Add(-time.Minute).Unix()}, []VerifierOption{WithMaxAge(time.Hour)}, false},
+		{"MaxAgeExceeded", public, map[string]interface{}{"iat": now.Add(-2 * time.Hour).Unix()}, []VerifierOption{WithMaxAge(time.Hour)}, true},
+		{"MaxAgeWithoutIssueDate", public, map[string]interface{}{}, []VerifierOption{WithMaxAge(time.Hour)}, true},
+		{"RequiredClaims", public, map[string]interface{}{"sub": "test", "jti": "id"}, []VerifierOption{WithRequiredClaims("sub", "jti")}, false},
+		{"RequiredClaimMissing", public, map[string]interface{}{"sub": "test"}, []VerifierOption{WithRequiredClaims("sub", "jti")}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc, err := s.Sign(tt.content)
+			if err != nil {
+				t.Fatalf("Failed to sign token: %s", err.Error())
+			}
+			v, err := NewVerifier(tt.key, tt.opts...)
+			if err != nil {
+				t.Fatalf("Failed to create verifier: %s", err.Error())
+			}
+			_, err = v.Verify(string(enc))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verifier.Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewVerifier(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Failed to generate keys for testing: %s", err.Error())
+	}
+	if _, err := NewVerifier(ed25519.PublicKey("test")); err == nil {
+		t.Error("NewVerifier() accepted invalid public key")
+	}
+	if _, err := NewVerifier(public, WithLeeway(-time.Second)); err == nil {
+		t.Error("NewVerifier() accepted negative leeway")
+	}
+	if _, err := NewVerifier(public, WithMaxAge(-time.Second)); err == nil {
+		t.Error("NewVerifier() accepted negative maximum age")
+	}
+	v, err := NewVerifier(public)
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %s", err.Error())
+	}
+	if _, err := v.Verify("test"); err == nil {
+		t.Error("Verifier.Verify() accepted invalid token")
+	}
+}
@@ -0,0 +1,57 @@
+package jwt
+
+import (
+	"crypto/ecdh"
+	"crypto/sha512"
+	"errors"
+	"math/big"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+// curve25519P is the prime 2^255 - 19 both Curve25519 and Edwards25519 are defined over
+var curve25519P = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+
+// X25519PrivateKey converts an Ed25519 private key to the X25519 private key of the same secret as defined in RFC 8032 and RFC 7748
+// Prefer separate keys for signing and encryption, the conversion is meant for recipients that only publish a single Ed25519 key
+func X25519PrivateKey(key ed25519.PrivateKey) (*ecdh.PrivateKey, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, errors.New("key is not a valid private key")
+	}
+	// Ed25519 uses the lower half of the SHA-512 hash of the seed as scalar which X25519 clamps the same way
+	h := sha512.Sum512(key.Seed())
+	return ecdh.X25519().NewPrivateKey(h[:32])
+}
+
+// X25519PublicKey converts an Ed25519 public key to the X25519 public key of the same point using the birational map u = (1 + y) / (1 - y) defined in RFC 7748
+func X25519PublicKey(key ed25519.PublicKey) (*ecdh.PublicKey, error) {
+	if len(key) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKey
+	}
+	// The key contains y in little endian with the sign of x in the most significant bit
+	be := make([]byte, len(key))
+	for i := range key {
+		be[len(key)-1-i] = key[i]
+	}
+	be[0] &= 0x7f
+	y := new(big.Int).SetBytes(be)
+	if y.Cmp(curve25519P) >= 0 {
+		return nil, ErrInvalidKey
+	}
+
+	denominator := new(big.Int).Sub(big.NewInt(1), y)
+	denominator.Mod(denominator, curve25519P)
+	if denominator.Sign() == 0 {
+		return nil, ErrInvalidKey
+	}
+	u := new(big.Int).Add(big.NewInt(1), y)
+	u.Mul(u, denominator.ModInverse(denominator, curve25519P))
+	u.Mod(u, curve25519P)
+
+	out := make([]byte, 32)
+	u.FillBytes(out)
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return ecdh.X25519().NewPublicKey(out)
+}